@@ -15,11 +15,11 @@ func (c *Client) GetAgent(ctx context.Context, agentID int32) (*Agent, error) {
 	return &agent, nil
 }
 
-// ListAgents retrieves a list of all agents
+// ListAgents retrieves a list of all agents with pagination
 func (c *Client) ListAgents(ctx context.Context) ([]Agent, error) {
 	path := "/agent"
-	var agents []Agent
-	if err := c.doRequest(ctx, "GET", path, nil, &agents); err != nil {
+	agents, err := Paginate[Agent](ctx, c, path)
+	if err != nil {
 		return nil, fmt.Errorf("failed to list agents: %w", err)
 	}
 	return agents, nil
@@ -33,3 +33,39 @@ func (c *Client) DeleteAgent(ctx context.Context, agentID int32) error {
 	}
 	return nil
 }
+
+// UpdateAgentDisplayName updates the display name of an agent
+func (c *Client) UpdateAgentDisplayName(ctx context.Context, agentID int32, displayName string) error {
+	path := fmt.Sprintf("/agent/%d/display_name", agentID)
+	if err := c.doRequestNoContent(ctx, "PUT", path, displayName); err != nil {
+		return fmt.Errorf("failed to update agent display name: %w", err)
+	}
+	return nil
+}
+
+// UpdateAgentLocation sets the geographic location of an agent
+func (c *Client) UpdateAgentLocation(ctx context.Context, agentID int32, location AgentLocation) error {
+	path := fmt.Sprintf("/agent/%d/location", agentID)
+	if err := c.doRequestNoContent(ctx, "PUT", path, location); err != nil {
+		return fmt.Errorf("failed to update agent location: %w", err)
+	}
+	return nil
+}
+
+// UpdateAgentTimeZone updates the time zone of an agent (IANA name)
+func (c *Client) UpdateAgentTimeZone(ctx context.Context, agentID int32, timeZone string) error {
+	path := fmt.Sprintf("/agent/%d/timezone", agentID)
+	if err := c.doRequestNoContent(ctx, "PUT", path, timeZone); err != nil {
+		return fmt.Errorf("failed to update agent time zone: %w", err)
+	}
+	return nil
+}
+
+// MoveAgentToTeam assigns an agent to another team
+func (c *Client) MoveAgentToTeam(ctx context.Context, agentID, teamID int32) error {
+	path := fmt.Sprintf("/agent/%d/team", agentID)
+	if err := c.doRequestNoContent(ctx, "PUT", path, teamID); err != nil {
+		return fmt.Errorf("failed to move agent to team: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,113 @@
+package client
+
+import (
+	"context"
+	"fmt"
+)
+
+// GetAlertProfile retrieves details of a specific alert profile
+func (c *Client) GetAlertProfile(ctx context.Context, profileID int32) (*AlertProfile, error) {
+	path := fmt.Sprintf("/alert-profile/%d", profileID)
+	var profile AlertProfile
+	if err := c.doRequest(ctx, "GET", path, nil, &profile); err != nil {
+		return nil, fmt.Errorf("failed to get alert profile: %w", err)
+	}
+	return &profile, nil
+}
+
+// ListAlertProfiles retrieves all alert profiles with pagination
+func (c *Client) ListAlertProfiles(ctx context.Context) ([]AlertProfile, error) {
+	path := "/alert-profile"
+	profiles, err := Paginate[AlertProfile](ctx, c, path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list alert profiles: %w", err)
+	}
+	return profiles, nil
+}
+
+// CreateAlertProfile creates a new alert profile
+func (c *Client) CreateAlertProfile(ctx context.Context, req CreateAlertProfileRequest) (*AlertProfile, error) {
+	path := "/alert-profile"
+	var profile AlertProfile
+	if err := c.doRequest(ctx, "POST", path, req, &profile); err != nil {
+		return nil, fmt.Errorf("failed to create alert profile: %w", err)
+	}
+	return &profile, nil
+}
+
+// UpdateAlertProfile updates an existing alert profile
+// Note: API returns 204 No Content
+func (c *Client) UpdateAlertProfile(ctx context.Context, profileID int32, req UpdateAlertProfileRequest) (*AlertProfile, error) {
+	path := fmt.Sprintf("/alert-profile/%d", profileID)
+	if err := c.doRequestNoContent(ctx, "PUT", path, req); err != nil {
+		return nil, fmt.Errorf("failed to update alert profile: %w", err)
+	}
+
+	// Retrieve the updated profile
+	return c.GetAlertProfile(ctx, profileID)
+}
+
+// DeleteAlertProfile deletes an alert profile along with its bindings
+func (c *Client) DeleteAlertProfile(ctx context.Context, profileID int32) error {
+	path := fmt.Sprintf("/alert-profile/%d", profileID)
+	if err := c.doRequestNoContent(ctx, "DELETE", path, nil); err != nil {
+		return fmt.Errorf("failed to delete alert profile: %w", err)
+	}
+	return nil
+}
+
+// BindAlertProfileToDevice applies an alert profile to a device
+func (c *Client) BindAlertProfileToDevice(ctx context.Context, agentID, deviceID, profileID int32) error {
+	path := fmt.Sprintf("/agent/%d/device/%d/alert-profile/%d/binding", agentID, deviceID, profileID)
+	if err := c.doRequestNoContent(ctx, "POST", path, nil); err != nil {
+		return fmt.Errorf("failed to bind alert profile to device: %w", err)
+	}
+	return nil
+}
+
+// UnbindAlertProfileFromDevice removes an alert profile from a device
+func (c *Client) UnbindAlertProfileFromDevice(ctx context.Context, agentID, deviceID, profileID int32) error {
+	path := fmt.Sprintf("/agent/%d/device/%d/alert-profile/%d/binding", agentID, deviceID, profileID)
+	if err := c.doRequestNoContent(ctx, "DELETE", path, nil); err != nil {
+		return fmt.Errorf("failed to unbind alert profile from device: %w", err)
+	}
+	return nil
+}
+
+// ListDeviceAlertProfiles retrieves the alert profiles applied to a device with pagination
+func (c *Client) ListDeviceAlertProfiles(ctx context.Context, agentID, deviceID int32) ([]AlertProfile, error) {
+	path := fmt.Sprintf("/agent/%d/device/%d/alert-profile/binding", agentID, deviceID)
+	profiles, err := Paginate[AlertProfile](ctx, c, path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list device alert profiles: %w", err)
+	}
+	return profiles, nil
+}
+
+// BindAlertProfileToAgent applies an alert profile to an agent (collector)
+func (c *Client) BindAlertProfileToAgent(ctx context.Context, agentID, profileID int32) error {
+	path := fmt.Sprintf("/agent/%d/alert-profile/%d/binding", agentID, profileID)
+	if err := c.doRequestNoContent(ctx, "POST", path, nil); err != nil {
+		return fmt.Errorf("failed to bind alert profile to agent: %w", err)
+	}
+	return nil
+}
+
+// UnbindAlertProfileFromAgent removes an alert profile from an agent (collector)
+func (c *Client) UnbindAlertProfileFromAgent(ctx context.Context, agentID, profileID int32) error {
+	path := fmt.Sprintf("/agent/%d/alert-profile/%d/binding", agentID, profileID)
+	if err := c.doRequestNoContent(ctx, "DELETE", path, nil); err != nil {
+		return fmt.Errorf("failed to unbind alert profile from agent: %w", err)
+	}
+	return nil
+}
+
+// ListAgentAlertProfiles retrieves the alert profiles applied to an agent with pagination
+func (c *Client) ListAgentAlertProfiles(ctx context.Context, agentID int32) ([]AlertProfile, error) {
+	path := fmt.Sprintf("/agent/%d/alert-profile/binding", agentID)
+	profiles, err := Paginate[AlertProfile](ctx, c, path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list agent alert profiles: %w", err)
+	}
+	return profiles, nil
+}
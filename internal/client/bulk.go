@@ -0,0 +1,109 @@
+package client
+
+import (
+	"context"
+	"errors"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// bulkWorkers bounds the number of concurrent requests made by the bulk
+// helpers. The rate limiter, when configured, still applies to each request.
+const bulkWorkers = 8
+
+// DeviceRef identifies a device on a collector
+type DeviceRef struct {
+	AgentID  int32
+	DeviceID int32
+}
+
+// BindTagToDevices binds a tag to every device using a bounded worker pool.
+// The returned slice is parallel to devices and holds nil for each device
+// that was bound successfully.
+func (c *Client) BindTagToDevices(ctx context.Context, tagID int32, devices []DeviceRef) []error {
+	return forEachDevice(ctx, devices, func(ctx context.Context, _ int, d DeviceRef) error {
+		return c.BindTagToDevice(ctx, d.AgentID, d.DeviceID, tagID)
+	})
+}
+
+// UnbindTagFromDevices removes a tag from every device using a bounded worker
+// pool. Devices that no longer carry the tag, or no longer exist, count as
+// unbound. The returned slice is parallel to devices.
+func (c *Client) UnbindTagFromDevices(ctx context.Context, tagID int32, devices []DeviceRef) []error {
+	return forEachDevice(ctx, devices, func(ctx context.Context, _ int, d DeviceRef) error {
+		err := c.UnbindTagFromDevice(ctx, d.AgentID, d.DeviceID, tagID)
+		var notFound *NotFoundError
+		if errors.As(err, &notFound) {
+			return nil
+		}
+		return err
+	})
+}
+
+// DevicesWithTag returns the subset of devices that currently carry the tag,
+// in the order given. Devices that no longer exist are left out.
+func (c *Client) DevicesWithTag(ctx context.Context, tagID int32, devices []DeviceRef) ([]DeviceRef, error) {
+	return c.DevicesWithTags(ctx, []int32{tagID}, devices)
+}
+
+// DevicesWithTags returns the subset of devices that currently carry every
+// one of the tags, in the order given. Each device's tags are listed once,
+// however many tags are checked. Devices that no longer exist are left out.
+func (c *Client) DevicesWithTags(ctx context.Context, tagIDs []int32, devices []DeviceRef) ([]DeviceRef, error) {
+	tagged := make([]bool, len(devices))
+	errs := forEachDevice(ctx, devices, func(ctx context.Context, i int, d DeviceRef) error {
+		tags, err := c.ListDeviceTags(ctx, d.AgentID, d.DeviceID)
+		if err != nil {
+			var notFound *NotFoundError
+			if errors.As(err, &notFound) {
+				return nil
+			}
+			return err
+		}
+		bound := make(map[int32]bool, len(tags))
+		for _, tag := range tags {
+			bound[tag.ID] = true
+		}
+		tagged[i] = true
+		for _, tagID := range tagIDs {
+			if !bound[tagID] {
+				tagged[i] = false
+				break
+			}
+		}
+		return nil
+	})
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+
+	var result []DeviceRef
+	for i, d := range devices {
+		if tagged[i] {
+			result = append(result, d)
+		}
+	}
+	return result, nil
+}
+
+// forEachDevice runs fn for every device on at most bulkWorkers goroutines
+// and returns the errors parallel to devices. A failure does not cancel the
+// remaining calls, so callers can report every failed device.
+func forEachDevice(ctx context.Context, devices []DeviceRef, fn func(context.Context, int, DeviceRef) error) []error {
+	errs := make([]error, len(devices))
+	var g errgroup.Group
+	g.SetLimit(bulkWorkers)
+	for i, d := range devices {
+		i, d := i, d
+		g.Go(func() error {
+			if err := ctx.Err(); err != nil {
+				errs[i] = err
+				return nil
+			}
+			errs[i] = fn(ctx, i, d)
+			return nil
+		})
+	}
+	_ = g.Wait()
+	return errs
+}
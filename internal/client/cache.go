@@ -0,0 +1,154 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	pathpkg "path"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// sharedFetchTimeout bounds a coalesced fetch, which no longer follows the
+// deadline of the caller that started it
+const sharedFetchTimeout = 5 * time.Minute
+
+// responseCache is an opt-in, TTL-bounded cache of GET response bodies keyed
+// by request path. Concurrent identical GETs share a single HTTP call, and
+// any mutation invalidates the cached entries of the collection it touches.
+type responseCache struct {
+	ttl   time.Duration
+	group singleflight.Group
+
+	mu         sync.Mutex
+	entries    map[string]cacheEntry
+	generation uint64 // bumped on every invalidation to discard in-flight fills
+}
+
+type cacheEntry struct {
+	body    json.RawMessage
+	expires time.Time
+}
+
+func newResponseCache(ttl time.Duration) *responseCache {
+	return &responseCache{
+		ttl:     ttl,
+		entries: make(map[string]cacheEntry),
+	}
+}
+
+// get returns the cached body for path, calling fetch to fill the cache on a
+// miss. Concurrent misses for the same path are coalesced into one fetch.
+func (rc *responseCache) get(ctx context.Context, path string, fetch func(context.Context) (json.RawMessage, error)) (json.RawMessage, error) {
+	rc.mu.Lock()
+	entry, ok := rc.entries[path]
+	generation := rc.generation
+	rc.mu.Unlock()
+	if ok && time.Now().Before(entry.expires) {
+		return entry.body, nil
+	}
+
+	v, err := doShared(ctx, &rc.group, path, func(ctx context.Context) (interface{}, error) {
+		body, err := fetch(ctx)
+		if err != nil {
+			return nil, err
+		}
+		rc.mu.Lock()
+		if rc.generation == generation {
+			rc.entries[path] = cacheEntry{body: body, expires: time.Now().Add(rc.ttl)}
+		}
+		rc.mu.Unlock()
+		return body, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.(json.RawMessage), nil
+}
+
+// doShared runs fn once for all concurrent callers with the same key. fn runs
+// on a context detached from the caller that started it, so one caller giving
+// up does not fail the others; each caller still returns when its own ctx is done.
+func doShared(ctx context.Context, group *singleflight.Group, key string, fn func(context.Context) (interface{}, error)) (interface{}, error) {
+	ch := group.DoChan(key, func() (interface{}, error) {
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), sharedFetchTimeout)
+		defer cancel()
+		return fn(ctx)
+	})
+	select {
+	case res := <-ch:
+		return res.Val, res.Err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// bindingLists maps collections to the binding lists that embed their
+// objects, which go stale when one of the objects is updated or deleted
+var bindingLists = map[string][]string{
+	"/custom-tag":      {"/agent/*/device/*/custom-tag/binding"},
+	"/alert-profile":   {"/agent/*/alert-profile/binding", "/agent/*/device/*/alert-profile/binding"},
+	"/contact/webhook": {"/alert-profile/*/contact/binding"},
+}
+
+// invalidate drops every cached entry belonging to the collection that a
+// mutation of path touches, and the binding lists referencing a mutated object
+func (rc *responseCache) invalidate(path string) {
+	base, _, _ := strings.Cut(path, "?")
+	collection := collectionOf(base)
+	var patterns []string
+	if base != collection {
+		patterns = bindingLists[collection]
+	}
+
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	rc.generation++
+	for key := range rc.entries {
+		base, _, _ := strings.Cut(key, "?")
+		if base == collection || strings.HasPrefix(base, collection+"/") || matchesAny(patterns, base) {
+			delete(rc.entries, key)
+		}
+	}
+}
+
+func matchesAny(patterns []string, path string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := pathpkg.Match(pattern, path); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// collectionOf returns the collection a path belongs to: the path truncated
+// before its last numeric ID segment, e.g. /custom-tag/5 -> /custom-tag and
+// /agent/1/device/2/importance -> /agent/1/device. Custom driver associations
+// are created under the driver and device but listed at /custom-driver/association.
+func collectionOf(path string) string {
+	path, _, _ = strings.Cut(path, "?")
+	if strings.HasPrefix(path, "/custom-driver/") && strings.HasSuffix(path, "/association") {
+		return "/custom-driver/association"
+	}
+	segments := strings.Split(path, "/")
+	for i := len(segments) - 1; i >= 0; i-- {
+		if isNumeric(segments[i]) {
+			return strings.Join(segments[:i], "/")
+		}
+	}
+	return path
+}
+
+func isNumeric(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
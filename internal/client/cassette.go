@@ -0,0 +1,442 @@
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+)
+
+// RecordMode selects whether a CassetteTransport records or replays traffic
+type RecordMode string
+
+const (
+	RecordModeOff    RecordMode = "off"
+	RecordModeRecord RecordMode = "record"
+	RecordModeReplay RecordMode = "replay"
+)
+
+// redactedHeaders are replaced with a placeholder before a cassette is written
+var redactedHeaders = []string{"X-Api-Key", "Authorization", "Cookie", "Set-Cookie"}
+
+// redactedFields are JSON body fields whose values are replaced with a
+// placeholder: SNMP communities and keys, webhook URLs and headers, and
+// custom driver credentials
+var redactedFields = map[string]bool{
+	"read_community":     true,
+	"write_community":    true,
+	"authentication_key": true,
+	"encryption_key":     true,
+	"url":                true,
+	"headers":            true,
+	"password":           true,
+}
+
+const redactedValue = "REDACTED"
+
+// cassetteLocks serialises writes to the same cassette from every transport
+// in the process, e.g. aliased provider blocks
+var cassetteLocks sync.Map // path -> *sync.Mutex
+
+// replayClaims counts, per cassette, the replaying transports in the process
+// that have claimed a session, so the nth transport to send a request
+// replays the nth recorded session
+var replayClaims sync.Map // path -> *int
+
+// Cassette is a recorded sequence of HTTP interactions
+type Cassette struct {
+	Interactions []Interaction `yaml:"interactions"`
+}
+
+// Interaction is one recorded request/response pair. Session numbers the
+// transport that recorded it, e.g. the terraform plan or apply process;
+// interactions without one form a single session.
+type Interaction struct {
+	Session  int              `yaml:"session,omitempty"`
+	Request  RecordedRequest  `yaml:"request"`
+	Response RecordedResponse `yaml:"response"`
+}
+
+// RecordedRequest is the request half of an Interaction
+type RecordedRequest struct {
+	Method  string      `yaml:"method"`
+	URL     string      `yaml:"url"`
+	Headers http.Header `yaml:"headers,omitempty"`
+	Body    string      `yaml:"body,omitempty"`
+}
+
+// RecordedResponse is the response half of an Interaction
+type RecordedResponse struct {
+	StatusCode int         `yaml:"status_code"`
+	Headers    http.Header `yaml:"headers,omitempty"`
+	Body       string      `yaml:"body,omitempty"`
+}
+
+// CassetteTransport is an http.RoundTripper that records request/response
+// pairs to a YAML file, or replays them from one without touching the network.
+// Credentials are redacted before anything is written to disk.
+type CassetteTransport struct {
+	path string
+	mode RecordMode
+	next http.RoundTripper
+
+	mu       sync.Mutex
+	cassette Cassette // loaded for replay
+	used     []bool
+	session  int  // session recorded to, or replayed from in recorded order
+	started  bool // whether session has been assigned yet
+}
+
+// NewCassetteTransport opens the cassette at path. In record mode each
+// interaction sent through next is appended to the file as soon as it
+// completes, as part of a new session that follows whatever an earlier
+// process (e.g. terraform plan before apply) or transport recorded there.
+// In replay mode the file must exist and next is never called.
+func NewCassetteTransport(path string, mode RecordMode, next http.RoundTripper) (*CassetteTransport, error) {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	t := &CassetteTransport{path: path, mode: mode, next: next}
+
+	switch mode {
+	case RecordModeRecord:
+	case RecordModeReplay:
+		cassette, err := loadCassette(path)
+		if err != nil {
+			return nil, err
+		}
+		t.cassette = cassette
+		t.used = make([]bool, len(t.cassette.Interactions))
+	default:
+		return nil, fmt.Errorf("unsupported record mode %q, expected %q or %q", mode, RecordModeRecord, RecordModeReplay)
+	}
+	return t, nil
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *CassetteTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := readRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+	if t.mode == RecordModeReplay {
+		return t.replay(req, body)
+	}
+	return t.record(req, body)
+}
+
+func (t *CassetteTransport) record(req *http.Request, body string) (*http.Response, error) {
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	respBody, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+
+	in := Interaction{
+		Request: RecordedRequest{
+			Method:  req.Method,
+			URL:     req.URL.String(),
+			Headers: redact(req.Header),
+			Body:    redactBody(body),
+		},
+		Response: RecordedResponse{
+			StatusCode: resp.StatusCode,
+			Headers:    redact(resp.Header),
+			Body:       redactBody(string(respBody)),
+		},
+	}
+	if err := t.append(in); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// replay returns the first unused interaction of the current session with
+// the same method, path, query and body, so repeated identical requests
+// replay in recorded order. A request the current session cannot answer
+// moves replay on to the next session that can, so an apply is not served
+// what an earlier plan recorded once the two diverge.
+func (t *CassetteTransport) replay(req *http.Request, body string) (*http.Response, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if !t.started {
+		t.session = t.claimSession()
+		t.started = true
+	}
+	i := t.find(req, body, func(in Interaction) bool { return in.Session == t.session })
+	if i < 0 {
+		i = t.find(req, body, func(in Interaction) bool { return in.Session > t.session })
+	}
+	if i < 0 {
+		return nil, fmt.Errorf("no recorded interaction in %s for %s %s", t.path, req.Method, req.URL.RequestURI())
+	}
+
+	in := t.cassette.Interactions[i]
+	t.used[i] = true
+	t.session = in.Session
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", in.Response.StatusCode, http.StatusText(in.Response.StatusCode)),
+		StatusCode:    in.Response.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        in.Response.Headers.Clone(),
+		Body:          io.NopCloser(bytes.NewReader([]byte(in.Response.Body))),
+		ContentLength: int64(len(in.Response.Body)),
+		Request:       req,
+	}, nil
+}
+
+// find returns the index of the first unused interaction in a session
+// accepted by inSession that matches req, or -1
+func (t *CassetteTransport) find(req *http.Request, body string, inSession func(Interaction) bool) int {
+	for i, in := range t.cassette.Interactions {
+		if !t.used[i] && inSession(in) && matches(in.Request, req, body) {
+			return i
+		}
+	}
+	return -1
+}
+
+// matches compares on the request URI rather than the full URL, so a
+// cassette recorded against one region replays against any base URL
+// with the same path prefix. Bodies are compared after redaction, as
+// recorded.
+func matches(recorded RecordedRequest, req *http.Request, body string) bool {
+	if recorded.Method != req.Method || recorded.Body != redactBody(body) {
+		return false
+	}
+	r, err := http.NewRequest(recorded.Method, recorded.URL, nil)
+	if err != nil {
+		return false
+	}
+	return r.URL.RequestURI() == req.URL.RequestURI()
+}
+
+// claimSession returns the session this transport replays from first: the
+// one after those claimed by earlier transports in the process, or the last
+// session once every one has been claimed
+func (t *CassetteTransport) claimSession() int {
+	var sessions []int
+	for _, in := range t.cassette.Interactions {
+		if len(sessions) == 0 || in.Session > sessions[len(sessions)-1] {
+			sessions = append(sessions, in.Session)
+		}
+	}
+	if len(sessions) == 0 {
+		return 0
+	}
+	lock, _ := cassetteLocks.LoadOrStore(t.path, &sync.Mutex{})
+	lock.(*sync.Mutex).Lock()
+	defer lock.(*sync.Mutex).Unlock()
+
+	claimed, _ := replayClaims.LoadOrStore(t.path, new(int))
+	n := claimed.(*int)
+	i := *n
+	*n++
+	if i >= len(sessions) {
+		i = len(sessions) - 1
+	}
+	return sessions[i]
+}
+
+// append writes in to the end of the cassette. The first interaction a
+// transport records opens a new session numbered after every session
+// already in the file.
+func (t *CassetteTransport) append(in Interaction) error {
+	lock, _ := cassetteLocks.LoadOrStore(t.path, &sync.Mutex{})
+	lock.(*sync.Mutex).Lock()
+	defer lock.(*sync.Mutex).Unlock()
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	newFile := false
+	if !t.started {
+		cassette, err := loadCassette(t.path)
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+		t.session = 1
+		for _, recorded := range cassette.Interactions {
+			if recorded.Session >= t.session {
+				t.session = recorded.Session + 1
+			}
+		}
+		t.started = true
+		newFile = len(cassette.Interactions) == 0
+	}
+	in.Session = t.session
+
+	data, err := yaml.Marshal(&Cassette{Interactions: []Interaction{in}})
+	if err != nil {
+		return fmt.Errorf("failed to encode cassette: %w", err)
+	}
+	flags := os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+	if !newFile {
+		// Only the list item, indented to continue the interactions list
+		data = bytes.TrimPrefix(data, []byte("interactions:\n"))
+		flags = os.O_WRONLY | os.O_APPEND
+	}
+	f, err := os.OpenFile(t.path, flags, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to write cassette: %w", err)
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write cassette: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to write cassette: %w", err)
+	}
+	return nil
+}
+
+func loadCassette(path string) (Cassette, error) {
+	var cassette Cassette
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return cassette, fmt.Errorf("failed to read cassette: %w", err)
+	}
+	if err := yaml.Unmarshal(data, &cassette); err != nil {
+		return cassette, fmt.Errorf("failed to parse cassette %s: %w", path, err)
+	}
+	return cassette, nil
+}
+
+// readRequestBody reads the request body and puts an unread copy back
+func readRequestBody(req *http.Request) (string, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return "", nil
+	}
+	data, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return "", fmt.Errorf("failed to read request body: %w", err)
+	}
+	req.Body = io.NopCloser(bytes.NewReader(data))
+	return string(data), nil
+}
+
+func redact(h http.Header) http.Header {
+	h = h.Clone()
+	for _, name := range redactedHeaders {
+		if _, ok := h[http.CanonicalHeaderKey(name)]; ok {
+			h.Set(name, redactedValue)
+		}
+	}
+	return h
+}
+
+// redactBody masks the values of redactedFields, and custom driver parameter
+// values, in a JSON body. Bodies that are not JSON, or contain nothing to
+// mask, are returned unchanged.
+func redactBody(body string) string {
+	var v interface{}
+	if body == "" || json.Unmarshal([]byte(body), &v) != nil {
+		return body
+	}
+	if !redactJSON(v) {
+		return body
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return body
+	}
+	return string(data)
+}
+
+// redactJSON masks sensitive values in place and reports whether it changed v
+func redactJSON(v interface{}) bool {
+	changed := false
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for key, value := range v {
+			switch {
+			case redactedFields[key]:
+				if masked, ok := maskValue(value); ok {
+					v[key] = masked
+					changed = true
+				}
+			case key == "parameters":
+				if redactParameters(value) {
+					changed = true
+				}
+			default:
+				if redactJSON(value) {
+					changed = true
+				}
+			}
+		}
+	case []interface{}:
+		for _, item := range v {
+			if redactJSON(item) {
+				changed = true
+			}
+		}
+	}
+	return changed
+}
+
+// redactParameters masks custom driver parameters: every value set on an
+// association, since the body does not say which are SECRET_TEXT, and the
+// default value of SECRET_TEXT parameter declarations
+func redactParameters(v interface{}) bool {
+	params, ok := v.([]interface{})
+	if !ok {
+		return redactJSON(v)
+	}
+	changed := false
+	for _, p := range params {
+		param, ok := p.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		field := "value"
+		if _, declared := param["value_type"]; declared {
+			if param["value_type"] != "SECRET_TEXT" {
+				continue
+			}
+			field = "default_value"
+		}
+		if masked, ok := maskValue(param[field]); ok {
+			param[field] = masked
+			changed = true
+		}
+	}
+	return changed
+}
+
+// maskValue replaces a non-empty string, or each value of an object such as
+// webhook headers, with redactedValue
+func maskValue(v interface{}) (interface{}, bool) {
+	switch v := v.(type) {
+	case string:
+		if v == "" || v == redactedValue {
+			return v, false
+		}
+		return redactedValue, true
+	case map[string]interface{}:
+		changed := false
+		for key, value := range v {
+			if masked, ok := maskValue(value); ok {
+				v[key] = masked
+				changed = true
+			}
+		}
+		return v, changed
+	}
+	return v, false
+}
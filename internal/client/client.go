@@ -4,17 +4,17 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
-	"strings"
+	"sync"
 	"time"
 )
 
 const (
 	defaultTimeout  = 30 * time.Second
 	defaultPageSize = 100
-	maxRetries      = 3
 	Version         = "1.0.0"
 )
 
@@ -25,11 +25,68 @@ type NotFoundError struct {
 
 func (e *NotFoundError) Error() string { return e.Message }
 
+// Sentinel errors for classifying API failures with errors.Is
+var (
+	ErrConflict     = errors.New("conflict")
+	ErrUnauthorized = errors.New("unauthorized")
+	ErrRateLimited  = errors.New("rate limited")
+	ErrValidation   = errors.New("validation failed")
+)
+
+// APIError represents a non-2xx API response other than 404
+type APIError struct {
+	StatusCode int
+	Code       string // ErrorResponse.Code, if the body carried one
+	Message    string
+	Method     string
+	Path       string
+	RequestID  string        // X-Request-Id response header, if present
+	RetryAfter time.Duration // Retry-After response header, if present
+}
+
+func (e *APIError) Error() string {
+	msg := fmt.Sprintf("API error (status %d) on %s %s", e.StatusCode, e.Method, e.Path)
+	if e.Code != "" {
+		msg += fmt.Sprintf(" [%s]", e.Code)
+	}
+	if e.Message != "" {
+		msg += ": " + e.Message
+	}
+	if e.RequestID != "" {
+		msg += fmt.Sprintf(" (request ID: %s)", e.RequestID)
+	}
+	return msg
+}
+
+// Is reports whether the error belongs to the failure class of target,
+// so callers can write errors.Is(err, client.ErrConflict)
+func (e *APIError) Is(target error) bool {
+	switch target {
+	case ErrConflict:
+		return e.StatusCode == http.StatusConflict
+	case ErrUnauthorized:
+		return e.StatusCode == http.StatusUnauthorized || e.StatusCode == http.StatusForbidden
+	case ErrRateLimited:
+		return e.StatusCode == http.StatusTooManyRequests
+	case ErrValidation:
+		return e.StatusCode == http.StatusBadRequest || e.StatusCode == http.StatusUnprocessableEntity
+	}
+	return false
+}
+
 // Client represents the Domotz API client
 type Client struct {
-	BaseURL    string
-	APIKey     string
-	HTTPClient *http.Client
+	BaseURL     string
+	APIKey      string
+	HTTPClient  *http.Client
+	RetryPolicy RetryPolicy
+
+	limiter *rateLimiter
+	cache   *responseCache
+	devices *deviceSnapshots
+
+	deviceTypesMu sync.Mutex
+	deviceTypes   []DeviceType
 }
 
 // NewClient creates a new Domotz API client
@@ -40,16 +97,75 @@ func NewClient(baseURL, apiKey string) *Client {
 		HTTPClient: &http.Client{
 			Timeout: defaultTimeout,
 		},
+		RetryPolicy: DefaultRetryPolicy(),
 	}
 }
 
-// doRequest executes an HTTP request with authentication, error handling, and retries
+// SetRateLimit throttles the client to requestsPerSecond with the given burst.
+// The burst also caps the number of concurrent in-flight requests.
+func (c *Client) SetRateLimit(requestsPerSecond float64, burst int) {
+	c.limiter = newRateLimiter(requestsPerSecond, burst)
+}
+
+// SetCacheTTL enables caching of GET responses for ttl. Mutations through
+// the client invalidate the cached entries of the collection they touch.
+func (c *Client) SetCacheTTL(ttl time.Duration) {
+	c.cache = newResponseCache(ttl)
+}
+
+// SetBatchReads serves GetDevice from one ListDevices snapshot per agent,
+// falling back to the single-device endpoint on a miss
+func (c *Client) SetBatchReads(enabled bool) {
+	c.devices = nil
+	if enabled {
+		c.devices = newDeviceSnapshots()
+	}
+}
+
+// doRequest executes an HTTP request, serving GETs from the response cache when enabled
 func (c *Client) doRequest(ctx context.Context, method, path string, body interface{}, result interface{}) error {
+	if method != http.MethodGet {
+		defer c.invalidate(path)
+		return c.doRequestWithRetries(ctx, method, path, body, result)
+	}
+
+	if c.cache == nil {
+		return c.doRequestWithRetries(ctx, method, path, body, result)
+	}
+
+	raw, err := c.cache.get(ctx, path, func(ctx context.Context) (json.RawMessage, error) {
+		var raw json.RawMessage
+		err := c.doRequestWithRetries(ctx, method, path, body, &raw)
+		return raw, err
+	})
+	if err != nil {
+		return err
+	}
+	if result != nil && len(raw) > 0 {
+		if err := json.Unmarshal(raw, result); err != nil {
+			return fmt.Errorf("failed to unmarshal response: %w", err)
+		}
+	}
+	return nil
+}
+
+// invalidate drops cached reads affected by a mutation of path
+func (c *Client) invalidate(path string) {
+	if c.cache != nil {
+		c.cache.invalidate(path)
+	}
+	if c.devices != nil {
+		c.devices.invalidate(path)
+	}
+}
+
+// doRequestWithRetries executes an HTTP request with authentication, error handling, and retries
+func (c *Client) doRequestWithRetries(ctx context.Context, method, path string, body interface{}, result interface{}) error {
 	var lastErr error
 
-	for attempt := 0; attempt <= maxRetries; attempt++ {
+	for attempt := 0; attempt <= c.RetryPolicy.MaxRetries; attempt++ {
 		if attempt > 0 {
-			backoff := time.Duration(1<<uint(attempt-1)) * time.Second // 1s, 2s, 4s
+			backoff := c.RetryPolicy.backoff(attempt, lastErr)
 			select {
 			case <-ctx.Done():
 				return ctx.Err()
@@ -62,7 +178,7 @@ func (c *Client) doRequest(ctx context.Context, method, path string, body interf
 			return nil
 		}
 
-		if !isRetryableError(err) {
+		if !c.RetryPolicy.shouldRetry(method, err) {
 			return err
 		}
 		lastErr = err
@@ -88,6 +204,14 @@ func (c *Client) doRequestOnce(ctx context.Context, method, path string, body in
 		return fmt.Errorf("failed to create request: %w", err)
 	}
 
+	// Wait for the shared rate limiter, if configured
+	if c.limiter != nil {
+		if err := c.limiter.acquire(ctx, method, path); err != nil {
+			return err
+		}
+		defer c.limiter.release()
+	}
+
 	// Set headers
 	req.Header.Set("X-Api-Key", c.APIKey)
 	req.Header.Set("Content-Type", "application/json")
@@ -116,11 +240,21 @@ func (c *Client) doRequestOnce(ctx context.Context, method, path string, body in
 
 	// Handle other non-2xx status codes
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		apiErr := &APIError{
+			StatusCode: resp.StatusCode,
+			Method:     method,
+			Path:       path,
+			RequestID:  resp.Header.Get("X-Request-Id"),
+			RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()),
+		}
 		var errResp ErrorResponse
 		if err := json.Unmarshal(respBody, &errResp); err != nil {
-			return fmt.Errorf("API error (status %d): %s", resp.StatusCode, string(respBody))
+			apiErr.Message = string(respBody)
+		} else {
+			apiErr.Message = errResp.Message
+			apiErr.Code = errResp.Code
 		}
-		return fmt.Errorf("API error (status %d): %s", resp.StatusCode, errResp.Message)
+		return apiErr
 	}
 
 	// Parse successful response
@@ -133,18 +267,6 @@ func (c *Client) doRequestOnce(ctx context.Context, method, path string, body in
 	return nil
 }
 
-// isRetryableError checks if an error is retryable (rate limiting or transient errors)
-func isRetryableError(err error) bool {
-	if err == nil {
-		return false
-	}
-	errMsg := err.Error()
-	return strings.Contains(errMsg, "status 429") ||
-		strings.Contains(errMsg, "status 502") ||
-		strings.Contains(errMsg, "status 503") ||
-		strings.Contains(errMsg, "status 504")
-}
-
 // doRequestNoContent executes a request that expects no response body (e.g., DELETE)
 func (c *Client) doRequestNoContent(ctx context.Context, method, path string, body interface{}) error {
 	return c.doRequest(ctx, method, path, body, nil)
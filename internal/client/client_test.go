@@ -2,9 +2,21 @@ package client
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 )
 
 func TestNewClient(t *testing.T) {
@@ -89,3 +101,1116 @@ func TestDoRequest_UserAgent(t *testing.T) {
 		t.Errorf("Unexpected error: %v", err)
 	}
 }
+
+func TestDoRequest_APIError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Request-Id", "req-123")
+		w.WriteHeader(http.StatusConflict)
+		_, _ = w.Write([]byte(`{"message": "port already monitored", "code": "DUPLICATE"}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-key")
+
+	err := client.doRequest(context.Background(), "POST", "/agent/1/device/2/eye/tcp", nil, nil)
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("Expected *APIError, got %T: %v", err, err)
+	}
+	if apiErr.StatusCode != http.StatusConflict {
+		t.Errorf("Expected status %d, got %d", http.StatusConflict, apiErr.StatusCode)
+	}
+	if apiErr.Code != "DUPLICATE" || apiErr.Message != "port already monitored" {
+		t.Errorf("Unexpected code/message: %q/%q", apiErr.Code, apiErr.Message)
+	}
+	if apiErr.Method != "POST" || apiErr.Path != "/agent/1/device/2/eye/tcp" {
+		t.Errorf("Unexpected method/path: %s %s", apiErr.Method, apiErr.Path)
+	}
+	if apiErr.RequestID != "req-123" {
+		t.Errorf("Expected request ID req-123, got %s", apiErr.RequestID)
+	}
+	if !errors.Is(err, ErrConflict) {
+		t.Error("Expected errors.Is(err, ErrConflict)")
+	}
+}
+
+func TestAPIError_Classification(t *testing.T) {
+	tests := []struct {
+		status    int
+		sentinel  error
+		retryable bool
+	}{
+		{http.StatusBadRequest, ErrValidation, false},
+		{http.StatusUnauthorized, ErrUnauthorized, false},
+		{http.StatusForbidden, ErrUnauthorized, false},
+		{http.StatusConflict, ErrConflict, false},
+		{http.StatusUnprocessableEntity, ErrValidation, false},
+		{http.StatusTooManyRequests, ErrRateLimited, true},
+		{http.StatusBadGateway, nil, true},
+		{http.StatusServiceUnavailable, nil, true},
+		{http.StatusInternalServerError, nil, false},
+	}
+
+	sentinels := []error{ErrConflict, ErrUnauthorized, ErrRateLimited, ErrValidation}
+	for _, tt := range tests {
+		err := error(&APIError{StatusCode: tt.status})
+		for _, s := range sentinels {
+			if got, want := errors.Is(err, s), s == tt.sentinel; got != want {
+				t.Errorf("status %d: errors.Is(%v) = %v, want %v", tt.status, s, got, want)
+			}
+		}
+		if got := DefaultRetryPolicy().shouldRetry(http.MethodGet, err); got != tt.retryable {
+			t.Errorf("status %d: shouldRetry = %v, want %v", tt.status, got, tt.retryable)
+		}
+	}
+}
+
+func TestDoRequest_RetryAfter(t *testing.T) {
+	var attempts atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if attempts.Add(1) == 1 {
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-key")
+	client.RetryPolicy.MinBackoff = time.Millisecond
+	client.RetryPolicy.MaxBackoff = 2 * time.Second
+
+	start := time.Now()
+	if err := client.doRequest(context.Background(), "GET", "", nil, nil); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if n := attempts.Load(); n != 2 {
+		t.Errorf("Expected 2 attempts, got %d", n)
+	}
+	if elapsed := time.Since(start); elapsed < time.Second {
+		t.Errorf("Expected Retry-After delay of 1s to be honored, waited %s", elapsed)
+	}
+}
+
+func TestDoRequest_RetryAfterBeyondMaxBackoff(t *testing.T) {
+	var attempts atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts.Add(1)
+		w.Header().Set("Retry-After", "3600")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-key")
+	client.RetryPolicy.MaxBackoff = time.Second
+
+	err := client.doRequest(context.Background(), "GET", "", nil, nil)
+	if !errors.Is(err, ErrRateLimited) {
+		t.Fatalf("Expected rate limited error, got %v", err)
+	}
+	if n := attempts.Load(); n != 1 {
+		t.Errorf("Expected 1 attempt, got %d", n)
+	}
+}
+
+func TestDoRequest_RetryOnStatus(t *testing.T) {
+	var attempts atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts.Add(1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-key")
+	client.RetryPolicy = RetryPolicy{
+		MaxRetries:    2,
+		MinBackoff:    time.Millisecond,
+		MaxBackoff:    time.Millisecond,
+		RetryOnStatus: []int{http.StatusInternalServerError},
+	}
+
+	err := client.doRequest(context.Background(), "GET", "", nil, nil)
+	if err == nil {
+		t.Fatal("Expected error after retries are exhausted")
+	}
+	if n := attempts.Load(); n != 3 {
+		t.Errorf("Expected 3 attempts, got %d", n)
+	}
+}
+
+func TestDoRequest_RetryConnectionReset(t *testing.T) {
+	for _, tt := range []struct {
+		method   string
+		attempts int32
+	}{
+		{http.MethodGet, 2},
+		{http.MethodPut, 2},
+		{http.MethodDelete, 2},
+		// The server may have processed the POST before the connection broke
+		{http.MethodPost, 1},
+	} {
+		var attempts atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if attempts.Add(1) == 1 {
+				conn, _, err := w.(http.Hijacker).Hijack()
+				if err != nil {
+					t.Errorf("Hijack failed: %v", err)
+					return
+				}
+				_ = conn.Close()
+				return
+			}
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write([]byte(`{}`))
+		}))
+
+		client := NewClient(server.URL, "test-key")
+		client.RetryPolicy.MinBackoff = time.Millisecond
+		client.RetryPolicy.MaxBackoff = time.Millisecond
+
+		err := client.doRequest(context.Background(), tt.method, "", map[string]string{}, nil)
+		if tt.attempts > 1 && err != nil {
+			t.Errorf("%s: unexpected error: %v", tt.method, err)
+		}
+		if tt.attempts == 1 && err == nil {
+			t.Errorf("%s: expected the connection error to be returned", tt.method)
+		}
+		if n := attempts.Load(); n != tt.attempts {
+			t.Errorf("%s: expected %d attempts, got %d", tt.method, tt.attempts, n)
+		}
+		server.Close()
+	}
+}
+
+func TestRetryPolicy_Backoff(t *testing.T) {
+	policy := RetryPolicy{MinBackoff: 100 * time.Millisecond, MaxBackoff: time.Second}
+
+	for attempt := 1; attempt <= 10; attempt++ {
+		if d := policy.backoff(attempt, nil); d < 0 || d > time.Second {
+			t.Errorf("attempt %d: backoff %s outside [0, 1s]", attempt, d)
+		}
+	}
+	if d := policy.backoff(1, nil); d > 100*time.Millisecond {
+		t.Errorf("attempt 1: backoff %s exceeds min backoff", d)
+	}
+
+	err := &APIError{StatusCode: http.StatusTooManyRequests, RetryAfter: 500 * time.Millisecond}
+	if d := policy.backoff(1, err); d != 500*time.Millisecond {
+		t.Errorf("Expected Retry-After to take precedence, got %s", d)
+	}
+
+	// A Retry-After up to MaxBackoff is waited out in full; a longer one is
+	// not retried at all
+	policy.RetryOnStatus = []int{http.StatusTooManyRequests}
+	err = &APIError{StatusCode: http.StatusTooManyRequests, RetryAfter: time.Second}
+	if !policy.shouldRetry(http.MethodGet, err) {
+		t.Errorf("Expected Retry-After equal to max backoff to be retried")
+	}
+	if d := policy.backoff(1, err); d != time.Second {
+		t.Errorf("Expected Retry-After of 1s, got %s", d)
+	}
+	err = &APIError{StatusCode: http.StatusTooManyRequests, RetryAfter: time.Second + time.Millisecond}
+	if policy.shouldRetry(http.MethodGet, err) {
+		t.Errorf("Expected Retry-After beyond max backoff not to be retried")
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	tests := map[string]time.Duration{
+		"":                              0,
+		"7":                             7 * time.Second,
+		"-1":                            0,
+		"garbage":                       0,
+		"Mon, 01 Jan 2024 12:00:30 GMT": 30 * time.Second,
+		"Mon, 01 Jan 2024 11:00:00 GMT": 0,
+	}
+	for value, want := range tests {
+		if got := parseRetryAfter(value, now); got != want {
+			t.Errorf("parseRetryAfter(%q) = %s, want %s", value, got, want)
+		}
+	}
+}
+
+func TestDoRequest_RateLimit(t *testing.T) {
+	var inFlight, maxInFlight atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			prev := maxInFlight.Load()
+			if n <= prev || maxInFlight.CompareAndSwap(prev, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-key")
+	client.SetRateLimit(50, 2)
+
+	start := time.Now()
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := client.doRequest(context.Background(), "GET", "", nil, nil); err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if n := maxInFlight.Load(); n > 2 {
+		t.Errorf("Expected at most 2 requests in flight, got %d", n)
+	}
+	// 8 requests with a burst of 2 at 50 req/s need at least 6 * 20ms
+	if elapsed := time.Since(start); elapsed < 100*time.Millisecond {
+		t.Errorf("Expected requests to be throttled, finished in %s", elapsed)
+	}
+}
+
+func TestDoRequest_RateLimitContextCanceled(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-key")
+	client.SetRateLimit(0.1, 1)
+
+	// The first request consumes the only token
+	if err := client.doRequest(context.Background(), "GET", "", nil, nil); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := client.doRequest(ctx, "GET", "", nil, nil); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected context deadline error, got %v", err)
+	}
+}
+
+func TestPaginate(t *testing.T) {
+	const total = 250
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		size, _ := strconv.Atoi(r.URL.Query().Get("page_size"))
+		page, _ := strconv.Atoi(r.URL.Query().Get("page_number"))
+		agents := []Agent{}
+		for id := (page-1)*size + 1; id <= page*size && id <= total; id++ {
+			agents = append(agents, Agent{ID: int32(id)})
+		}
+		_ = json.NewEncoder(w).Encode(agents)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-key")
+
+	agents, err := client.ListAgents(context.Background())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(agents) != total {
+		t.Fatalf("Expected %d agents, got %d", total, len(agents))
+	}
+	for i, a := range agents {
+		if a.ID != int32(i+1) {
+			t.Fatalf("Expected agent %d at index %d, got %d", i+1, i, a.ID)
+		}
+	}
+	if n := requests.Load(); n != 3 {
+		t.Errorf("Expected 3 page requests, got %d", n)
+	}
+}
+
+func TestListDevicesFiltered(t *testing.T) {
+	var query url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query()
+		_ = json.NewEncoder(w).Encode([]Device{})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-key")
+
+	_, err := client.ListDevicesFiltered(context.Background(), 1, DeviceListFilter{Importance: "VITAL", Status: "ONLINE"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if got := query.Get("importance"); got != "VITAL" {
+		t.Errorf("Expected importance=VITAL, got %q", got)
+	}
+	if got := query.Get("status"); got != "ONLINE" {
+		t.Errorf("Expected status=ONLINE, got %q", got)
+	}
+	if query.Has("protocol") {
+		t.Errorf("Expected no protocol parameter, got %q", query.Get("protocol"))
+	}
+	if query.Get("page_size") == "" {
+		t.Error("Expected pagination parameters alongside the filter")
+	}
+}
+
+func TestPaginate_IgnoredPagination(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		tags := make([]Tag, defaultPageSize)
+		for i := range tags {
+			tags[i] = Tag{ID: int32(i + 1)}
+		}
+		_ = json.NewEncoder(w).Encode(TagsResponse{Tags: tags})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-key")
+
+	tags, err := client.ListTags(context.Background())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(tags) != defaultPageSize {
+		t.Errorf("Expected %d tags, got %d", defaultPageSize, len(tags))
+	}
+	if n := requests.Load(); n != 2 {
+		t.Errorf("Expected 2 page requests, got %d", n)
+	}
+}
+
+func TestDoRequest_Cache(t *testing.T) {
+	var requests atomic.Int32
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			requests.Add(1)
+			<-release
+		}
+		_ = json.NewEncoder(w).Encode(TagsResponse{Tags: []Tag{{ID: 1, Name: "prod"}}})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-key")
+	client.SetCacheTTL(time.Minute)
+
+	// Concurrent identical reads share one HTTP call
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			tag, err := client.GetTag(context.Background(), 1)
+			if err != nil || tag.Name != "prod" {
+				t.Errorf("Unexpected result: %v, %v", tag, err)
+			}
+		}()
+	}
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	if n := requests.Load(); n != 1 {
+		t.Fatalf("Expected 1 request for concurrent reads, got %d", n)
+	}
+
+	// Subsequent reads are served from the cache
+	if _, err := client.ListTags(context.Background()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if n := requests.Load(); n != 1 {
+		t.Fatalf("Expected cached read, got %d requests", n)
+	}
+
+	// A mutation in the collection invalidates the cached list
+	if err := client.DeleteTag(context.Background(), 1); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, err := client.ListTags(context.Background()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if n := requests.Load(); n != 2 {
+		t.Errorf("Expected read after mutation to hit the API, got %d requests", n)
+	}
+}
+
+func TestDoRequest_CacheCancelledCaller(t *testing.T) {
+	var requests atomic.Int32
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		<-release
+		_ = json.NewEncoder(w).Encode(TagsResponse{Tags: []Tag{{ID: 1, Name: "prod"}}})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-key")
+	client.SetCacheTTL(time.Minute)
+
+	// The first caller starts the shared fetch, then gives up
+	ctx, cancel := context.WithCancel(context.Background())
+	first := make(chan error, 1)
+	go func() {
+		_, err := client.GetTag(ctx, 1)
+		first <- err
+	}()
+	time.Sleep(50 * time.Millisecond)
+
+	second := make(chan error, 1)
+	go func() {
+		tag, err := client.GetTag(context.Background(), 1)
+		if err == nil && tag.Name != "prod" {
+			t.Errorf("Unexpected tag: %v", tag)
+		}
+		second <- err
+	}()
+	time.Sleep(50 * time.Millisecond)
+
+	cancel()
+	if err := <-first; !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected the cancelled caller to return context.Canceled, got %v", err)
+	}
+	close(release)
+	if err := <-second; err != nil {
+		t.Errorf("Expected the waiting caller to get the shared result, got %v", err)
+	}
+	if n := requests.Load(); n != 1 {
+		t.Errorf("Expected 1 request, got %d", n)
+	}
+}
+
+func TestDoRequest_CacheInvalidatesAssociations(t *testing.T) {
+	var lists atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			lists.Add(1)
+			_, _ = w.Write([]byte(`[]`))
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-key")
+	client.SetCacheTTL(time.Minute)
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		if _, err := client.ListCustomDriverAssociations(ctx); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+	if n := lists.Load(); n != 1 {
+		t.Fatalf("Expected cached association list, got %d requests", n)
+	}
+
+	if _, err := client.CreateCustomDriverAssociation(ctx, 4, 1, 2, CreateCustomDriverAssociationRequest{}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, err := client.ListCustomDriverAssociations(ctx); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if n := lists.Load(); n != 2 {
+		t.Errorf("Expected association list to be read again after a mutation, got %d requests", n)
+	}
+}
+
+func TestDoRequest_CacheInvalidatesBindingLists(t *testing.T) {
+	var mu sync.Mutex
+	gets := map[string]int{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			mu.Lock()
+			gets[r.URL.Path]++
+			mu.Unlock()
+			_, _ = w.Write([]byte(`[]`))
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-key")
+	client.SetCacheTTL(time.Minute)
+	ctx := context.Background()
+
+	readAll := func() {
+		t.Helper()
+		if _, err := client.ListDeviceTags(ctx, 1, 2); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if _, err := client.ListDeviceAlertProfiles(ctx, 1, 2); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if _, err := client.ListAgentAlertProfiles(ctx, 1); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if _, err := client.ListAlertProfileContacts(ctx, 3); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+	expect := func(want map[string]int) {
+		t.Helper()
+		mu.Lock()
+		defer mu.Unlock()
+		for path, n := range want {
+			if gets[path] != n {
+				t.Errorf("Expected %d GET %s, got %d", n, path, gets[path])
+			}
+		}
+	}
+	const (
+		deviceTags     = "/agent/1/device/2/custom-tag/binding"
+		deviceProfiles = "/agent/1/device/2/alert-profile/binding"
+		agentProfiles  = "/agent/1/alert-profile/binding"
+		contacts       = "/alert-profile/3/contact/binding"
+	)
+
+	readAll()
+	readAll()
+	expect(map[string]int{deviceTags: 1, deviceProfiles: 1, agentProfiles: 1, contacts: 1})
+
+	if err := client.DeleteTag(ctx, 7); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	readAll()
+	expect(map[string]int{deviceTags: 2, deviceProfiles: 1, agentProfiles: 1, contacts: 1})
+
+	if err := client.DeleteWebhookContact(ctx, 8); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	readAll()
+	expect(map[string]int{deviceTags: 2, deviceProfiles: 1, agentProfiles: 1, contacts: 2})
+
+	if err := client.DeleteAlertProfile(ctx, 9); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	readAll()
+	expect(map[string]int{deviceTags: 2, deviceProfiles: 2, agentProfiles: 2, contacts: 3})
+}
+
+func TestCollectionOf(t *testing.T) {
+	tests := map[string]string{
+		"/custom-tag":                                   "/custom-tag",
+		"/custom-tag/5":                                 "/custom-tag",
+		"/agent/1/device/2/custom-tag/3/binding":        "/agent/1/device/2/custom-tag",
+		"/agent/1/device/2/eye/tcp/9":                   "/agent/1/device/2/eye/tcp",
+		"/agent/1/device/2/user_data/name":              "/agent/1/device",
+		"/agent/1/device/external-host":                 "/agent",
+		"/agent/1/device?page_size=100&page_number=2":   "/agent",
+		"/custom-driver/4/agent/1/device/2/association": "/custom-driver/association",
+		"/custom-driver/association/8":                  "/custom-driver/association",
+	}
+	for path, want := range tests {
+		if got := collectionOf(path); got != want {
+			t.Errorf("collectionOf(%q) = %q, want %q", path, got, want)
+		}
+	}
+}
+
+func TestGetDevice_BatchReads(t *testing.T) {
+	var listRequests, getRequests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/agent/1/device":
+			listRequests.Add(1)
+			_ = json.NewEncoder(w).Encode([]Device{
+				{ID: 10, AgentID: 1, DisplayName: "router"},
+				{ID: 11, AgentID: 1, DisplayName: "switch"},
+			})
+		case r.Method == http.MethodGet:
+			getRequests.Add(1)
+			_ = json.NewEncoder(w).Encode(Device{ID: 12, AgentID: 1, DisplayName: "fresh"})
+		default:
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-key")
+	client.SetBatchReads(true)
+	ctx := context.Background()
+
+	for _, id := range []int32{10, 11, 10} {
+		device, err := client.GetDevice(ctx, 1, id)
+		if err != nil || device.ID != id {
+			t.Fatalf("GetDevice(%d) = %v, %v", id, device, err)
+		}
+	}
+	if n := listRequests.Load(); n != 1 {
+		t.Errorf("Expected 1 list request, got %d", n)
+	}
+	if n := getRequests.Load(); n != 0 {
+		t.Errorf("Expected no single-device requests, got %d", n)
+	}
+
+	// A snapshot miss falls back to the single-device endpoint
+	if device, err := client.GetDevice(ctx, 1, 12); err != nil || device.DisplayName != "fresh" {
+		t.Fatalf("GetDevice(12) = %v, %v", device, err)
+	}
+	if n := getRequests.Load(); n != 1 {
+		t.Errorf("Expected 1 single-device request, got %d", n)
+	}
+
+	// A mutated device is read individually afterwards
+	if err := client.UpdateDeviceImportance(ctx, 1, 10, "VITAL"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, err := client.GetDevice(ctx, 1, 10); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, err := client.GetDevice(ctx, 1, 11); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if n := getRequests.Load(); n != 2 {
+		t.Errorf("Expected mutated device to be read individually, got %d single-device requests", n)
+	}
+	if n := listRequests.Load(); n != 1 {
+		t.Errorf("Expected snapshot to be kept for other devices, got %d list requests", n)
+	}
+}
+
+func TestCassetteTransport_RecordReplay(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		switch r.Method {
+		case http.MethodGet:
+			w.Write([]byte(`{"id":10,"agent_id":1,"display_name":"core-switch"}`))
+		case http.MethodPut:
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "cassette.yaml")
+	ctx := context.Background()
+
+	recorder, err := NewCassetteTransport(path, RecordModeRecord, nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	client := NewClient(server.URL, "secret-api-key")
+	client.HTTPClient.Transport = recorder
+	if _, err := client.GetDevice(ctx, 1, 10); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// Each interaction is on disk as soon as it completes
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !strings.Contains(string(data), "core-switch") {
+		t.Errorf("Expected the first interaction in the cassette:\n%s", data)
+	}
+	if err := client.UpdateDeviceImportance(ctx, 1, 10, "VITAL"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	data, err = os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if strings.Contains(string(data), "secret-api-key") {
+		t.Errorf("Cassette leaks the API key:\n%s", data)
+	}
+	if !strings.Contains(string(data), "REDACTED") {
+		t.Errorf("Expected redacted X-Api-Key in cassette:\n%s", data)
+	}
+
+	// Replay against a closed server, as if filed with a bug report
+	server.Close()
+	recorded := requests.Load()
+	player, err := NewCassetteTransport(path, RecordModeReplay, nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	client = NewClient("https://api.example.com", "other-key")
+	client.HTTPClient.Transport = player
+	device, err := client.GetDevice(ctx, 1, 10)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if device.DisplayName != "core-switch" {
+		t.Errorf("Expected replayed display name core-switch, got %q", device.DisplayName)
+	}
+	if err := client.UpdateDeviceImportance(ctx, 1, 10, "VITAL"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if requests.Load() != recorded {
+		t.Errorf("Replay reached the network")
+	}
+
+	// Each interaction replays once, and requests must match exactly
+	client.RetryPolicy.MaxRetries = 0
+	if _, err := client.GetDevice(ctx, 1, 10); err == nil || !strings.Contains(err.Error(), "no recorded interaction") {
+		t.Errorf("Expected exhausted cassette error, got %v", err)
+	}
+	if err := client.UpdateDeviceImportance(ctx, 1, 10, "FLOATING"); err == nil {
+		t.Errorf("Expected error for request body that was never recorded")
+	}
+}
+
+func TestCassetteTransport_RedactsBodies(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if r.Method == http.MethodPut {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		// Echo the request back, as the API does for created objects
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(body)
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "cassette.yaml")
+	ctx := context.Background()
+
+	recorder, err := NewCassetteTransport(path, RecordModeRecord, nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	client := NewClient(server.URL, "test-key")
+	client.HTTPClient.Transport = recorder
+
+	snmp := SNMPAuthentication{
+		Version:                SNMPVersionV3AuthPriv,
+		ReadCommunity:          "secret-read-community",
+		Username:               "monitor",
+		AuthenticationProtocol: "SHA",
+		AuthenticationKey:      "secret-auth-key",
+		EncryptionProtocol:     "AES",
+		EncryptionKey:          "secret-priv-key",
+	}
+	if err := client.SetSNMPAuthentication(ctx, 1, 10, snmp); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	contact := WebhookContactRequest{
+		Name:    "pager",
+		URL:     "https://hooks.example.com/secret-token",
+		Headers: map[string]string{"Authorization": "Bearer secret-bearer"},
+	}
+	if _, err := client.CreateWebhookContact(ctx, contact); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	driver := CustomDriverRequest{
+		Name:   "ups",
+		Script: "function validate() {}",
+		Parameters: []CustomDriverParameter{
+			{Name: "port", ValueType: "NUMBER", DefaultValue: "161"},
+			{Name: "token", ValueType: "SECRET_TEXT", DefaultValue: "secret-default"},
+		},
+	}
+	if _, err := client.CreateCustomDriver(ctx, driver); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	association := CreateCustomDriverAssociationRequest{
+		Credentials: &CustomDriverCredentials{Username: "admin", Password: "secret-password"},
+		Parameters:  []CustomDriverParameterValue{{Name: "token", Value: "secret-parameter"}},
+	}
+	if _, err := client.CreateCustomDriverAssociation(ctx, 4, 1, 10, association); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	for _, secret := range []string{
+		"secret-read-community", "secret-auth-key", "secret-priv-key", "secret-token",
+		"secret-bearer", "secret-default", "secret-password", "secret-parameter",
+	} {
+		if strings.Contains(string(data), secret) {
+			t.Errorf("Cassette leaks %q:\n%s", secret, data)
+		}
+	}
+	for _, kept := range []string{"monitor", "Authorization", "161", "token"} {
+		if !strings.Contains(string(data), kept) {
+			t.Errorf("Expected %q to be kept in cassette:\n%s", kept, data)
+		}
+	}
+
+	// Redacted requests still replay
+	player, err := NewCassetteTransport(path, RecordModeReplay, nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	client.HTTPClient.Transport = player
+	if err := client.SetSNMPAuthentication(ctx, 1, 10, snmp); err != nil {
+		t.Errorf("Unexpected replay error: %v", err)
+	}
+	if _, err := client.CreateWebhookContact(ctx, contact); err != nil {
+		t.Errorf("Unexpected replay error: %v", err)
+	}
+}
+
+func TestCassetteTransport_Appends(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "cassette.yaml")
+	ctx := context.Background()
+
+	// Two transports open at once, as for aliased providers, then a third
+	var clients []*Client
+	for i := 0; i < 2; i++ {
+		recorder, err := NewCassetteTransport(path, RecordModeRecord, nil)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		client := NewClient(server.URL, "test-key")
+		client.HTTPClient.Transport = recorder
+		clients = append(clients, client)
+	}
+	if err := clients[0].UpdateDeviceImportance(ctx, 1, 10, "VITAL"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := clients[1].UpdateDeviceImportance(ctx, 1, 11, "FLOATING"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	recorder, err := NewCassetteTransport(path, RecordModeRecord, nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	clients[0].HTTPClient.Transport = recorder
+	if err := clients[0].UpdateDeviceImportance(ctx, 1, 12, "VITAL"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	server.Close()
+	player, err := NewCassetteTransport(path, RecordModeReplay, nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	client := NewClient("https://api.example.com", "other-key")
+	client.HTTPClient.Transport = player
+	client.RetryPolicy.MaxRetries = 0
+	for _, update := range []struct {
+		id         int32
+		importance string
+	}{{10, "VITAL"}, {11, "FLOATING"}, {12, "VITAL"}} {
+		if err := client.UpdateDeviceImportance(ctx, 1, update.id, update.importance); err != nil {
+			t.Errorf("Expected device %d to be replayed: %v", update.id, err)
+		}
+	}
+}
+
+func TestCassetteTransport_Sessions(t *testing.T) {
+	displayName := "before"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPut {
+			displayName = "after"
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		fmt.Fprintf(w, `{"id":10,"agent_id":1,"display_name":%q}`, displayName)
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "cassette.yaml")
+	ctx := context.Background()
+
+	// terraform plan reads the device, then terraform apply reads it, renames
+	// it and reads it back
+	run := func(mode RecordMode, baseURL string, apply bool) string {
+		t.Helper()
+		transport, err := NewCassetteTransport(path, mode, nil)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		client := NewClient(baseURL, "test-key")
+		client.HTTPClient.Transport = transport
+		client.RetryPolicy.MaxRetries = 0
+		device, err := client.GetDevice(ctx, 1, 10)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if apply {
+			if err := client.UpdateDeviceDisplayName(ctx, 1, 10, "after"); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if device, err = client.GetDevice(ctx, 1, 10); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+		}
+		return device.DisplayName
+	}
+	run(RecordModeRecord, server.URL, false)
+	run(RecordModeRecord, server.URL, true)
+	server.Close()
+
+	if got := run(RecordModeReplay, "https://api.example.com", false); got != "before" {
+		t.Errorf("Expected plan to replay its own read, got %q", got)
+	}
+	// The second transport replays the apply session from its first request,
+	// so the read after the rename is not served the earlier identical read
+	if got := run(RecordModeReplay, "https://api.example.com", true); got != "after" {
+		t.Errorf("Expected apply to replay its own read, got %q", got)
+	}
+}
+
+func TestCassetteTransport_Fixture(t *testing.T) {
+	client := NewClient("https://api-eu-west-1-cell-1.domotz.com/public-api/v1", "any")
+	transport, err := NewCassetteTransport("testdata/get_device_not_found.yaml", RecordModeReplay, nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	client.HTTPClient.Transport = transport
+
+	device, err := client.GetDevice(context.Background(), 200891, 42)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if device.DisplayName != "core-switch" {
+		t.Errorf("Expected display name core-switch, got %q", device.DisplayName)
+	}
+
+	var notFound *NotFoundError
+	if _, err := client.GetDevice(context.Background(), 200891, 43); !errors.As(err, &notFound) {
+		t.Errorf("Expected NotFoundError, got %v", err)
+	}
+}
+
+func TestNewCassetteTransport_InvalidMode(t *testing.T) {
+	if _, err := NewCassetteTransport(filepath.Join(t.TempDir(), "c.yaml"), RecordMode("replay-all"), nil); err == nil {
+		t.Error("Expected error for unsupported record mode")
+	}
+	if _, err := NewCassetteTransport(filepath.Join(t.TempDir(), "missing.yaml"), RecordModeReplay, nil); err == nil {
+		t.Error("Expected error for missing cassette in replay mode")
+	}
+}
+
+func TestResolveDeviceType(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if r.URL.Path != "/type/device/base" {
+			t.Errorf("Unexpected path %s", r.URL.Path)
+		}
+		json.NewEncoder(w).Encode([]DeviceType{
+			{ID: 7, Identifier: "ROUTER", Label: "Router"},
+			{ID: 15, Identifier: "SWITCH", Label: "Network Switch"},
+		})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-key")
+	ctx := context.Background()
+
+	for value, want := range map[string]int32{"7": 7, "Router": 7, "router": 7, "network switch": 15, "SWITCH": 15} {
+		deviceType, err := client.ResolveDeviceType(ctx, value)
+		if err != nil || deviceType.ID != want {
+			t.Errorf("ResolveDeviceType(%q) = %v, %v; want ID %d", value, deviceType, err, want)
+		}
+	}
+
+	var notFound *NotFoundError
+	for _, value := range []string{"Toaster", "99"} {
+		if _, err := client.ResolveDeviceType(ctx, value); !errors.As(err, &notFound) {
+			t.Errorf("ResolveDeviceType(%q): expected NotFoundError, got %v", value, err)
+		}
+	}
+
+	if n := requests.Load(); n != 1 {
+		t.Errorf("Expected the catalogue to be fetched once, got %d requests", n)
+	}
+}
+
+func TestBindTagToDevices(t *testing.T) {
+	var inFlight, maxInFlight atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			m := maxInFlight.Load()
+			if n <= m || maxInFlight.CompareAndSwap(m, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+		if strings.Contains(r.URL.Path, "/device/13/") {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-key")
+	client.RetryPolicy.MaxRetries = 0
+
+	var devices []DeviceRef
+	for id := int32(1); id <= 3*bulkWorkers; id++ {
+		devices = append(devices, DeviceRef{AgentID: 1, DeviceID: id})
+	}
+
+	errs := client.BindTagToDevices(context.Background(), 5, devices)
+	if len(errs) != len(devices) {
+		t.Fatalf("Expected %d results, got %d", len(devices), len(errs))
+	}
+	for i, err := range errs {
+		var notFound *NotFoundError
+		if devices[i].DeviceID == 13 {
+			if !errors.As(err, &notFound) {
+				t.Errorf("Expected not found error for device 13, got %v", err)
+			}
+		} else if err != nil {
+			t.Errorf("Unexpected error for device %d: %v", devices[i].DeviceID, err)
+		}
+	}
+	if n := maxInFlight.Load(); n > bulkWorkers || n < 2 {
+		t.Errorf("Expected between 2 and %d concurrent requests, got %d", bulkWorkers, n)
+	}
+
+	// Unbinding a device that is already gone counts as success
+	for i, err := range client.UnbindTagFromDevices(context.Background(), 5, devices) {
+		if err != nil {
+			t.Errorf("Unexpected error for device %d: %v", devices[i].DeviceID, err)
+		}
+	}
+}
+
+func TestDevicesWithTags(t *testing.T) {
+	var requests atomic.Int32
+	bindings := map[string]string{
+		"/agent/1/device/1/custom-tag/binding": `[{"id":5},{"id":6}]`,
+		"/agent/1/device/2/custom-tag/binding": `[{"id":5}]`,
+		"/agent/1/device/3/custom-tag/binding": `[]`,
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		body, ok := bindings[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(body))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-key")
+	client.RetryPolicy.MaxRetries = 0
+	devices := []DeviceRef{{1, 1}, {1, 2}, {1, 3}, {1, 4}}
+
+	tagged, err := client.DevicesWithTags(context.Background(), []int32{5, 6}, devices)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(tagged) != 1 || tagged[0] != devices[0] {
+		t.Errorf("Expected only device 1 to carry both tags, got %v", tagged)
+	}
+	// One listing per device, not one per device and tag
+	if n := requests.Load(); n != int32(len(devices)) {
+		t.Errorf("Expected %d requests, got %d", len(devices), n)
+	}
+}
@@ -0,0 +1,85 @@
+package client
+
+import (
+	"context"
+	"fmt"
+)
+
+// GetWebhookContact retrieves details of a specific webhook contact
+func (c *Client) GetWebhookContact(ctx context.Context, contactID int32) (*WebhookContact, error) {
+	path := fmt.Sprintf("/contact/webhook/%d", contactID)
+	var contact WebhookContact
+	if err := c.doRequest(ctx, "GET", path, nil, &contact); err != nil {
+		return nil, fmt.Errorf("failed to get webhook contact: %w", err)
+	}
+	return &contact, nil
+}
+
+// ListWebhookContacts retrieves all webhook contacts with pagination
+func (c *Client) ListWebhookContacts(ctx context.Context) ([]WebhookContact, error) {
+	path := "/contact/webhook"
+	contacts, err := Paginate[WebhookContact](ctx, c, path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list webhook contacts: %w", err)
+	}
+	return contacts, nil
+}
+
+// CreateWebhookContact creates a new webhook contact
+func (c *Client) CreateWebhookContact(ctx context.Context, req WebhookContactRequest) (*WebhookContact, error) {
+	path := "/contact/webhook"
+	var contact WebhookContact
+	if err := c.doRequest(ctx, "POST", path, req, &contact); err != nil {
+		return nil, fmt.Errorf("failed to create webhook contact: %w", err)
+	}
+	return &contact, nil
+}
+
+// UpdateWebhookContact replaces the name, URL and headers of a webhook contact
+// Note: API returns 204 No Content
+func (c *Client) UpdateWebhookContact(ctx context.Context, contactID int32, req WebhookContactRequest) (*WebhookContact, error) {
+	path := fmt.Sprintf("/contact/webhook/%d", contactID)
+	if err := c.doRequestNoContent(ctx, "PUT", path, req); err != nil {
+		return nil, fmt.Errorf("failed to update webhook contact: %w", err)
+	}
+
+	// Retrieve the updated contact
+	return c.GetWebhookContact(ctx, contactID)
+}
+
+// DeleteWebhookContact deletes a webhook contact along with its alert profile bindings
+func (c *Client) DeleteWebhookContact(ctx context.Context, contactID int32) error {
+	path := fmt.Sprintf("/contact/webhook/%d", contactID)
+	if err := c.doRequestNoContent(ctx, "DELETE", path, nil); err != nil {
+		return fmt.Errorf("failed to delete webhook contact: %w", err)
+	}
+	return nil
+}
+
+// BindContactToAlertProfile makes an alert profile deliver its alerts to a contact
+func (c *Client) BindContactToAlertProfile(ctx context.Context, profileID, contactID int32) error {
+	path := fmt.Sprintf("/alert-profile/%d/contact/%d/binding", profileID, contactID)
+	if err := c.doRequestNoContent(ctx, "POST", path, nil); err != nil {
+		return fmt.Errorf("failed to bind contact to alert profile: %w", err)
+	}
+	return nil
+}
+
+// UnbindContactFromAlertProfile stops an alert profile delivering its alerts to a contact
+func (c *Client) UnbindContactFromAlertProfile(ctx context.Context, profileID, contactID int32) error {
+	path := fmt.Sprintf("/alert-profile/%d/contact/%d/binding", profileID, contactID)
+	if err := c.doRequestNoContent(ctx, "DELETE", path, nil); err != nil {
+		return fmt.Errorf("failed to unbind contact from alert profile: %w", err)
+	}
+	return nil
+}
+
+// ListAlertProfileContacts retrieves the contacts an alert profile delivers to with pagination
+func (c *Client) ListAlertProfileContacts(ctx context.Context, profileID int32) ([]WebhookContact, error) {
+	path := fmt.Sprintf("/alert-profile/%d/contact/binding", profileID)
+	contacts, err := Paginate[WebhookContact](ctx, c, path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list alert profile contacts: %w", err)
+	}
+	return contacts, nil
+}
@@ -0,0 +1,96 @@
+package client
+
+import (
+	"context"
+	"fmt"
+)
+
+// GetCustomDriver retrieves details of a specific custom driver
+func (c *Client) GetCustomDriver(ctx context.Context, driverID int32) (*CustomDriver, error) {
+	path := fmt.Sprintf("/custom-driver/%d", driverID)
+	var driver CustomDriver
+	if err := c.doRequest(ctx, "GET", path, nil, &driver); err != nil {
+		return nil, fmt.Errorf("failed to get custom driver: %w", err)
+	}
+	return &driver, nil
+}
+
+// ListCustomDrivers retrieves all custom drivers with pagination
+func (c *Client) ListCustomDrivers(ctx context.Context) ([]CustomDriver, error) {
+	path := "/custom-driver"
+	drivers, err := Paginate[CustomDriver](ctx, c, path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list custom drivers: %w", err)
+	}
+	return drivers, nil
+}
+
+// CreateCustomDriver creates a new custom driver
+func (c *Client) CreateCustomDriver(ctx context.Context, req CustomDriverRequest) (*CustomDriver, error) {
+	path := "/custom-driver"
+	var driver CustomDriver
+	if err := c.doRequest(ctx, "POST", path, req, &driver); err != nil {
+		return nil, fmt.Errorf("failed to create custom driver: %w", err)
+	}
+	return &driver, nil
+}
+
+// UpdateCustomDriver replaces the script and settings of a custom driver
+// Note: API returns 204 No Content
+func (c *Client) UpdateCustomDriver(ctx context.Context, driverID int32, req CustomDriverRequest) (*CustomDriver, error) {
+	path := fmt.Sprintf("/custom-driver/%d", driverID)
+	if err := c.doRequestNoContent(ctx, "PUT", path, req); err != nil {
+		return nil, fmt.Errorf("failed to update custom driver: %w", err)
+	}
+
+	// Retrieve the updated driver
+	return c.GetCustomDriver(ctx, driverID)
+}
+
+// DeleteCustomDriver deletes a custom driver along with its associations
+func (c *Client) DeleteCustomDriver(ctx context.Context, driverID int32) error {
+	path := fmt.Sprintf("/custom-driver/%d", driverID)
+	if err := c.doRequestNoContent(ctx, "DELETE", path, nil); err != nil {
+		return fmt.Errorf("failed to delete custom driver: %w", err)
+	}
+	return nil
+}
+
+// GetCustomDriverAssociation retrieves details of a specific custom driver association
+func (c *Client) GetCustomDriverAssociation(ctx context.Context, associationID int32) (*CustomDriverAssociation, error) {
+	path := fmt.Sprintf("/custom-driver/association/%d", associationID)
+	var association CustomDriverAssociation
+	if err := c.doRequest(ctx, "GET", path, nil, &association); err != nil {
+		return nil, fmt.Errorf("failed to get custom driver association: %w", err)
+	}
+	return &association, nil
+}
+
+// ListCustomDriverAssociations retrieves all custom driver associations with pagination
+func (c *Client) ListCustomDriverAssociations(ctx context.Context) ([]CustomDriverAssociation, error) {
+	path := "/custom-driver/association"
+	associations, err := Paginate[CustomDriverAssociation](ctx, c, path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list custom driver associations: %w", err)
+	}
+	return associations, nil
+}
+
+// CreateCustomDriverAssociation starts running a custom driver against a device
+func (c *Client) CreateCustomDriverAssociation(ctx context.Context, driverID, agentID, deviceID int32, req CreateCustomDriverAssociationRequest) (*CustomDriverAssociation, error) {
+	path := fmt.Sprintf("/custom-driver/%d/agent/%d/device/%d/association", driverID, agentID, deviceID)
+	var association CustomDriverAssociation
+	if err := c.doRequest(ctx, "POST", path, req, &association); err != nil {
+		return nil, fmt.Errorf("failed to create custom driver association: %w", err)
+	}
+	return &association, nil
+}
+
+// DeleteCustomDriverAssociation stops running a custom driver against a device
+func (c *Client) DeleteCustomDriverAssociation(ctx context.Context, associationID int32) error {
+	path := fmt.Sprintf("/custom-driver/association/%d", associationID)
+	if err := c.doRequestNoContent(ctx, "DELETE", path, nil); err != nil {
+		return fmt.Errorf("failed to delete custom driver association: %w", err)
+	}
+	return nil
+}
@@ -3,10 +3,18 @@ package client
 import (
 	"context"
 	"fmt"
+	"net/url"
 )
 
-// GetDevice retrieves details of a specific device
+// GetDevice retrieves details of a specific device.
+// With batch reads enabled, the device is served from the agent snapshot when present.
 func (c *Client) GetDevice(ctx context.Context, agentID, deviceID int32) (*Device, error) {
+	if c.devices != nil {
+		if device, ok := c.devices.lookup(ctx, c, agentID, deviceID); ok {
+			return device, nil
+		}
+	}
+
 	path := fmt.Sprintf("/agent/%d/device/%d", agentID, deviceID)
 	var device Device
 	if err := c.doRequest(ctx, "GET", path, nil, &device); err != nil {
@@ -15,16 +23,39 @@ func (c *Client) GetDevice(ctx context.Context, agentID, deviceID int32) (*Devic
 	return &device, nil
 }
 
-// ListDevices retrieves all devices for a specific agent
+// ListDevices retrieves all devices for a specific agent with pagination
 func (c *Client) ListDevices(ctx context.Context, agentID int32) ([]Device, error) {
+	return c.ListDevicesFiltered(ctx, agentID, DeviceListFilter{})
+}
+
+// ListDevicesFiltered retrieves the devices of an agent matching filter, with
+// pagination. Empty filter fields are not sent.
+func (c *Client) ListDevicesFiltered(ctx context.Context, agentID int32, filter DeviceListFilter) ([]Device, error) {
 	path := fmt.Sprintf("/agent/%d/device", agentID)
-	var devices []Device
-	if err := c.doRequest(ctx, "GET", path, nil, &devices); err != nil {
+	if query := filter.query(); query != "" {
+		path += "?" + query
+	}
+	devices, err := Paginate[Device](ctx, c, path)
+	if err != nil {
 		return nil, fmt.Errorf("failed to list devices: %w", err)
 	}
 	return devices, nil
 }
 
+func (f DeviceListFilter) query() string {
+	values := url.Values{}
+	if f.Importance != "" {
+		values.Set("importance", f.Importance)
+	}
+	if f.Protocol != "" {
+		values.Set("protocol", f.Protocol)
+	}
+	if f.Status != "" {
+		values.Set("status", f.Status)
+	}
+	return values.Encode()
+}
+
 // CreateDevice creates a new external IP device (external host)
 func (c *Client) CreateDevice(ctx context.Context, agentID int32, req CreateDeviceRequest) (*Device, error) {
 	path := fmt.Sprintf("/agent/%d/device/external-host", agentID)
@@ -35,6 +66,15 @@ func (c *Client) CreateDevice(ctx context.Context, agentID int32, req CreateDevi
 	return &device, nil
 }
 
+// UpdateDeviceDisplayName updates the display name of a device
+func (c *Client) UpdateDeviceDisplayName(ctx context.Context, agentID, deviceID int32, displayName string) error {
+	path := fmt.Sprintf("/agent/%d/device/%d/display_name", agentID, deviceID)
+	if err := c.doRequestNoContent(ctx, "PUT", path, displayName); err != nil {
+		return fmt.Errorf("failed to update device display name: %w", err)
+	}
+	return nil
+}
+
 // UpdateDeviceImportance updates the importance level of a device
 func (c *Client) UpdateDeviceImportance(ctx context.Context, agentID, deviceID int32, importance string) error {
 	path := fmt.Sprintf("/agent/%d/device/%d/importance", agentID, deviceID)
@@ -44,6 +84,24 @@ func (c *Client) UpdateDeviceImportance(ctx context.Context, agentID, deviceID i
 	return nil
 }
 
+// UpdateDeviceZone updates the zone of a device, read back as Details.Zone
+func (c *Client) UpdateDeviceZone(ctx context.Context, agentID, deviceID int32, zone string) error {
+	path := fmt.Sprintf("/agent/%d/device/%d/details/zone", agentID, deviceID)
+	if err := c.doRequestNoContent(ctx, "PUT", path, zone); err != nil {
+		return fmt.Errorf("failed to update device zone: %w", err)
+	}
+	return nil
+}
+
+// ClearDeviceZone removes the zone of a device
+func (c *Client) ClearDeviceZone(ctx context.Context, agentID, deviceID int32) error {
+	path := fmt.Sprintf("/agent/%d/device/%d/details/zone", agentID, deviceID)
+	if err := c.doRequestNoContent(ctx, "DELETE", path, nil); err != nil {
+		return fmt.Errorf("failed to clear device zone: %w", err)
+	}
+	return nil
+}
+
 // UpdateDeviceUserDataName updates the user_data name field of a device
 func (c *Client) UpdateDeviceUserDataName(ctx context.Context, agentID, deviceID int32, name string) error {
 	path := fmt.Sprintf("/agent/%d/device/%d/user_data/name", agentID, deviceID)
@@ -80,9 +138,35 @@ func (c *Client) UpdateDeviceUserDataType(ctx context.Context, agentID, deviceID
 	return nil
 }
 
+// ClearDeviceUserData resets a single user_data field (name, model, vendor or type)
+func (c *Client) ClearDeviceUserData(ctx context.Context, agentID, deviceID int32, field string) error {
+	path := fmt.Sprintf("/agent/%d/device/%d/user_data/%s", agentID, deviceID, field)
+	if err := c.doRequestNoContent(ctx, "DELETE", path, nil); err != nil {
+		return fmt.Errorf("failed to clear device user_data %s: %w", field, err)
+	}
+	return nil
+}
+
+// setDeviceUserDataString updates a string user_data field, or clears it when value is empty
+func (c *Client) setDeviceUserDataString(ctx context.Context, agentID, deviceID int32, field, value string,
+	update func(ctx context.Context, agentID, deviceID int32, value string) error) error {
+	if value == "" {
+		return c.ClearDeviceUserData(ctx, agentID, deviceID, field)
+	}
+	return update(ctx, agentID, deviceID, value)
+}
+
 // UpdateDevice updates an existing device by calling individual field update endpoints
-// This is a convenience method that calls the appropriate field-specific endpoints
+// This is a convenience method that calls the appropriate field-specific endpoints.
+// IP addresses have no field endpoint; changing them requires a new device.
 func (c *Client) UpdateDevice(ctx context.Context, agentID, deviceID int32, req UpdateDeviceRequest) (*Device, error) {
+	// Update display name if provided
+	if req.DisplayName != nil && *req.DisplayName != "" {
+		if err := c.UpdateDeviceDisplayName(ctx, agentID, deviceID, *req.DisplayName); err != nil {
+			return nil, err
+		}
+	}
+
 	// Update importance if provided
 	if req.Importance != nil && *req.Importance != "" {
 		if err := c.UpdateDeviceImportance(ctx, agentID, deviceID, *req.Importance); err != nil {
@@ -90,25 +174,44 @@ func (c *Client) UpdateDevice(ctx context.Context, agentID, deviceID int32, req
 		}
 	}
 
-	// Update user_data fields if provided
-	if req.UserData != nil {
-		if req.UserData.Name != "" {
-			if err := c.UpdateDeviceUserDataName(ctx, agentID, deviceID, req.UserData.Name); err != nil {
+	// Update or clear zone if provided
+	if req.Zone != nil {
+		var err error
+		if *req.Zone == "" {
+			err = c.ClearDeviceZone(ctx, agentID, deviceID)
+		} else {
+			err = c.UpdateDeviceZone(ctx, agentID, deviceID, *req.Zone)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Update or clear user_data fields if provided
+	if ud := req.UserData; ud != nil {
+		if ud.Name != nil {
+			if err := c.setDeviceUserDataString(ctx, agentID, deviceID, "name", *ud.Name, c.UpdateDeviceUserDataName); err != nil {
 				return nil, err
 			}
 		}
-		if req.UserData.Model != "" {
-			if err := c.UpdateDeviceUserDataModel(ctx, agentID, deviceID, req.UserData.Model); err != nil {
+		if ud.Model != nil {
+			if err := c.setDeviceUserDataString(ctx, agentID, deviceID, "model", *ud.Model, c.UpdateDeviceUserDataModel); err != nil {
 				return nil, err
 			}
 		}
-		if req.UserData.Vendor != "" {
-			if err := c.UpdateDeviceUserDataVendor(ctx, agentID, deviceID, req.UserData.Vendor); err != nil {
+		if ud.Vendor != nil {
+			if err := c.setDeviceUserDataString(ctx, agentID, deviceID, "vendor", *ud.Vendor, c.UpdateDeviceUserDataVendor); err != nil {
 				return nil, err
 			}
 		}
-		if req.UserData.Type != 0 {
-			if err := c.UpdateDeviceUserDataType(ctx, agentID, deviceID, req.UserData.Type); err != nil {
+		if ud.Type != nil {
+			var err error
+			if *ud.Type == 0 {
+				err = c.ClearDeviceUserData(ctx, agentID, deviceID, "type")
+			} else {
+				err = c.UpdateDeviceUserDataType(ctx, agentID, deviceID, *ud.Type)
+			}
+			if err != nil {
 				return nil, err
 			}
 		}
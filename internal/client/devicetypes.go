@@ -0,0 +1,48 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ListDeviceTypes retrieves the device type catalogue. The catalogue does not
+// change while the provider runs, so the first successful response is kept
+// for the lifetime of the client.
+func (c *Client) ListDeviceTypes(ctx context.Context) ([]DeviceType, error) {
+	c.deviceTypesMu.Lock()
+	defer c.deviceTypesMu.Unlock()
+
+	if c.deviceTypes != nil {
+		return c.deviceTypes, nil
+	}
+
+	var deviceTypes []DeviceType
+	if err := c.doRequest(ctx, "GET", "/type/device/base", nil, &deviceTypes); err != nil {
+		return nil, fmt.Errorf("failed to list device types: %w", err)
+	}
+	c.deviceTypes = deviceTypes
+	return deviceTypes, nil
+}
+
+// ResolveDeviceType finds a device type by numeric ID, label or identifier.
+// Labels and identifiers are matched case-insensitively.
+func (c *Client) ResolveDeviceType(ctx context.Context, value string) (*DeviceType, error) {
+	deviceTypes, err := c.ListDeviceTypes(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := strconv.ParseInt(value, 10, 32)
+	isID := err == nil
+	for _, t := range deviceTypes {
+		if (isID && t.ID == int32(id)) ||
+			(!isID && (strings.EqualFold(t.Label, value) || strings.EqualFold(t.Identifier, value))) {
+			return &t, nil
+		}
+	}
+	return nil, &NotFoundError{
+		Message: fmt.Sprintf("device type %q not found", value),
+	}
+}
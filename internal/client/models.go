@@ -14,14 +14,22 @@ type AgentStatus struct {
 	LastChange time.Time `json:"last_change"` // When status last changed
 }
 
+// AgentLocation is the geographic position of a collector's site
+type AgentLocation struct {
+	Latitude  string `json:"latitude"`
+	Longitude string `json:"longitude"`
+}
+
 // Agent represents a Domotz collector/agent
 type Agent struct {
-	ID           int32       `json:"id"`
-	DisplayName  string      `json:"display_name"`
-	Status       AgentStatus `json:"status"`
-	Team         Team        `json:"team"`
-	CreationTime time.Time   `json:"creation_time"`
-	OnlineAt     time.Time   `json:"online_at,omitempty"`
+	ID           int32          `json:"id"`
+	DisplayName  string         `json:"display_name"`
+	Status       AgentStatus    `json:"status"`
+	Team         Team           `json:"team"`
+	Location     *AgentLocation `json:"location,omitempty"`
+	TimeZone     string         `json:"timezone,omitempty"` // IANA name, e.g. Europe/London
+	CreationTime time.Time      `json:"creation_time"`
+	OnlineAt     time.Time      `json:"online_at,omitempty"`
 }
 
 // DeviceUserData represents custom metadata for a device
@@ -32,6 +40,21 @@ type DeviceUserData struct {
 	Type   int32  `json:"type,omitempty"`
 }
 
+// DeviceOS represents the operating system detected on a device
+type DeviceOS struct {
+	Name    string `json:"name,omitempty"`
+	Version string `json:"version,omitempty"`
+	Build   string `json:"build,omitempty"`
+}
+
+// DeviceDetails represents inventory details of a device
+type DeviceDetails struct {
+	Serial          string `json:"serial,omitempty"`
+	FirmwareVersion string `json:"firmware_version,omitempty"`
+	Room            string `json:"room,omitempty"`
+	Zone            string `json:"zone,omitempty"`
+}
+
 // Device represents a monitored device
 type Device struct {
 	ID                   int32          `json:"id"`
@@ -45,9 +68,22 @@ type Device struct {
 	AuthenticationStatus string         `json:"authentication_status,omitempty"`
 	Importance           string         `json:"importance,omitempty"` // VITAL, FLOATING
 	HWAddress            string         `json:"hw_address,omitempty"`
-	Zone                 string         `json:"zone,omitempty"`
 	FirstSeenAt          time.Time      `json:"first_seen_at,omitempty"`
 	LastStatusChange     time.Time      `json:"last_status_change,omitempty"`
+	Status               string         `json:"status,omitempty"`      // ONLINE, OFFLINE, DOWN
+	SNMPStatus           string         `json:"snmp_status,omitempty"` // e.g. CHECKING, NOT_FOUND, OK
+	AgentReachable       bool           `json:"agent_reachable"`
+	IsJammed             bool           `json:"is_jammed"`
+	GroupingType         string         `json:"grouping_type,omitempty"` // e.g. MAIN, ELEMENT
+	OS                   DeviceOS       `json:"os"`
+	Details              DeviceDetails  `json:"details"`
+}
+
+// DeviceListFilter holds the device list filters the API applies server-side
+type DeviceListFilter struct {
+	Importance string // VITAL, FLOATING
+	Protocol   string // IP, DUMMY, etc.
+	Status     string // ONLINE, OFFLINE, DOWN
 }
 
 // CreateDeviceRequest represents the request to create a new device
@@ -60,9 +96,19 @@ type CreateDeviceRequest struct {
 
 // UpdateDeviceRequest represents the request to update a device
 type UpdateDeviceRequest struct {
-	DisplayName *string         `json:"display_name,omitempty"`
-	UserData    *DeviceUserData `json:"user_data,omitempty"`
-	Importance  *string         `json:"importance,omitempty"`
+	DisplayName *string               `json:"display_name,omitempty"`
+	UserData    *UpdateDeviceUserData `json:"user_data,omitempty"`
+	Importance  *string               `json:"importance,omitempty"`
+	Zone        *string               `json:"zone,omitempty"` // empty clears the zone
+}
+
+// UpdateDeviceUserData represents user_data changes. Nil fields are left
+// unchanged; an empty string or zero type clears the field.
+type UpdateDeviceUserData struct {
+	Name   *string `json:"name,omitempty"`
+	Model  *string `json:"model,omitempty"`
+	Vendor *string `json:"vendor,omitempty"`
+	Type   *int32  `json:"type,omitempty"`
 }
 
 // Tag represents a custom tag
@@ -96,6 +142,173 @@ type DeviceTagBinding struct {
 	TagID    int32 `json:"tag_id"`
 }
 
+// AlertProfile represents an alert profile: the set of events that notify
+// the profile's contacts for the agents and devices bound to it
+type AlertProfile struct {
+	ID          int32                   `json:"id"`
+	Name        string                  `json:"name"`
+	Description string                  `json:"description,omitempty"`
+	Events      []string                `json:"events"`
+	Thresholds  []AlertProfileThreshold `json:"thresholds,omitempty"` // for device_variable_threshold
+}
+
+// AlertProfileThreshold is a condition on a device variable that raises a
+// device_variable_threshold alert
+type AlertProfileThreshold struct {
+	Variable string `json:"variable"` // variable label, e.g. "CPU Usage"
+	Operator string `json:"operator"` // see AlertThresholdOperators
+	Value    string `json:"value"`
+}
+
+// Alert profile event types
+const (
+	AlertEventDeviceDown              = "device_status_down"
+	AlertEventDeviceUp                = "device_status_up"
+	AlertEventDeviceIPChange          = "device_ip_change"
+	AlertEventDeviceVariableThreshold = "device_variable_threshold"
+	AlertEventSNMPEyeTrigger          = "device_snmp_eye_trigger"
+	AlertEventTCPEyeTrigger           = "device_tcp_eye_trigger"
+)
+
+// AlertProfileEvents lists every event type an alert profile can subscribe to
+var AlertProfileEvents = []string{
+	AlertEventDeviceDown,
+	AlertEventDeviceUp,
+	AlertEventDeviceIPChange,
+	AlertEventDeviceVariableThreshold,
+	AlertEventSNMPEyeTrigger,
+	AlertEventTCPEyeTrigger,
+}
+
+// Alert threshold operators
+const (
+	AlertThresholdGreaterThan = "greater_than"
+	AlertThresholdLessThan    = "less_than"
+	AlertThresholdEqual       = "equal"
+	AlertThresholdNotEqual    = "not_equal"
+)
+
+// AlertThresholdOperators lists every operator a threshold can compare with
+var AlertThresholdOperators = []string{
+	AlertThresholdGreaterThan,
+	AlertThresholdLessThan,
+	AlertThresholdEqual,
+	AlertThresholdNotEqual,
+}
+
+// CreateAlertProfileRequest represents the request to create an alert profile
+type CreateAlertProfileRequest struct {
+	Name        string                  `json:"name"`
+	Description string                  `json:"description,omitempty"`
+	Events      []string                `json:"events"`
+	Thresholds  []AlertProfileThreshold `json:"thresholds,omitempty"`
+}
+
+// UpdateAlertProfileRequest represents the request to update an alert profile.
+// Nil Thresholds are left unchanged; an empty slice removes them.
+type UpdateAlertProfileRequest struct {
+	Name        *string                 `json:"name,omitempty"`
+	Description *string                 `json:"description,omitempty"`
+	Events      []string                `json:"events,omitempty"`
+	Thresholds  []AlertProfileThreshold `json:"thresholds"`
+}
+
+// WebhookContact represents a notification contact that receives alerts as
+// HTTP POST requests
+type WebhookContact struct {
+	ID      int32             `json:"id"`
+	Name    string            `json:"name"`
+	URL     string            `json:"url"`
+	Headers map[string]string `json:"headers,omitempty"` // sent with every request, e.g. Authorization
+}
+
+// WebhookContactRequest represents the request to create or replace a webhook contact
+type WebhookContactRequest struct {
+	Name    string            `json:"name"`
+	URL     string            `json:"url"`
+	Headers map[string]string `json:"headers,omitempty"`
+}
+
+// CustomDriver represents a custom driver: a JavaScript integration that
+// runs on the collector and produces device variables
+type CustomDriver struct {
+	ID                  int32                   `json:"id"`
+	Name                string                  `json:"name"`
+	Description         string                  `json:"description,omitempty"`
+	Script              string                  `json:"script"`
+	MinimalSamplePeriod int32                   `json:"minimal_sample_period"` // seconds
+	Parameters          []CustomDriverParameter `json:"parameters,omitempty"`
+}
+
+// CustomDriverParameter declares an input of a custom driver
+type CustomDriverParameter struct {
+	Name         string `json:"name"`
+	Description  string `json:"description,omitempty"`
+	ValueType    string `json:"value_type"` // STRING, NUMBER, LIST, SECRET_TEXT
+	DefaultValue string `json:"default_value,omitempty"`
+}
+
+// CustomDriverRequest represents the request to create or replace a custom driver
+type CustomDriverRequest struct {
+	Name                string                  `json:"name"`
+	Description         string                  `json:"description,omitempty"`
+	Script              string                  `json:"script"`
+	MinimalSamplePeriod int32                   `json:"minimal_sample_period"`
+	Parameters          []CustomDriverParameter `json:"parameters,omitempty"`
+}
+
+// CustomDriverAssociation represents a custom driver running against a device.
+// Credentials are write-only and never returned by the API.
+type CustomDriverAssociation struct {
+	ID             int32                        `json:"id"`
+	CustomDriverID int32                        `json:"custom_driver_id"`
+	AgentID        int32                        `json:"agent_id"`
+	DeviceID       int32                        `json:"device_id"`
+	SamplePeriod   int32                        `json:"sample_period"` // seconds
+	Parameters     []CustomDriverParameterValue `json:"parameters,omitempty"`
+}
+
+// CustomDriverParameterValue sets a custom driver parameter for one association
+type CustomDriverParameterValue struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// CustomDriverCredentials are the device credentials passed to a custom driver
+type CustomDriverCredentials struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+// CreateCustomDriverAssociationRequest represents the request to associate a custom driver with a device
+type CreateCustomDriverAssociationRequest struct {
+	SamplePeriod int32                        `json:"sample_period,omitempty"` // defaults to the driver's minimal sample period
+	Credentials  *CustomDriverCredentials     `json:"credentials,omitempty"`
+	Parameters   []CustomDriverParameterValue `json:"parameters,omitempty"`
+}
+
+// SNMP authentication versions as named by the API
+const (
+	SNMPVersionV1           = "V1"
+	SNMPVersionV2           = "V2"
+	SNMPVersionV3NoAuth     = "V3_NO_AUTH"
+	SNMPVersionV3AuthNoPriv = "V3_AUTH_NO_PRIV"
+	SNMPVersionV3AuthPriv   = "V3_AUTH_PRIV"
+)
+
+// SNMPAuthentication holds the SNMP credentials Domotz uses for a device.
+// Communities and keys are write-only: the API omits them from responses.
+type SNMPAuthentication struct {
+	Version                string `json:"version"`
+	ReadCommunity          string `json:"read_community,omitempty"`
+	WriteCommunity         string `json:"write_community,omitempty"`
+	Username               string `json:"username,omitempty"`
+	AuthenticationProtocol string `json:"authentication_protocol,omitempty"` // MD5, SHA
+	AuthenticationKey      string `json:"authentication_key,omitempty"`
+	EncryptionProtocol     string `json:"encryption_protocol,omitempty"` // DES, AES
+	EncryptionKey          string `json:"encryption_key,omitempty"`
+}
+
 // SNMPSensor represents an SNMP OID sensor
 type SNMPSensor struct {
 	ID        int32  `json:"id"`
@@ -128,6 +341,15 @@ type CreateTCPSensorRequest struct {
 	Port int32 `json:"port"`
 }
 
+// DeviceType represents an entry of the device type catalogue
+type DeviceType struct {
+	ID           int32    `json:"id"`
+	Identifier   string   `json:"identifier"`
+	Label        string   `json:"label"`
+	Vital        bool     `json:"vital"`
+	Capabilities []string `json:"capabilities,omitempty"`
+}
+
 // Variable represents a device variable/metric
 type Variable struct {
 	ID            int32     `json:"id"`
@@ -0,0 +1,61 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// maxPages bounds how many pages a single list call may fetch, so an
+// endpoint that keeps returning full pages cannot loop forever
+const maxPages = 1000
+
+// query renders the pagination parameters as URL query arguments
+func (p PaginationParams) query() string {
+	return fmt.Sprintf("page_size=%d&page_number=%d", p.PageSize, p.PageNumber)
+}
+
+// Paginate fetches every page of a list endpoint that returns a JSON array
+func Paginate[T any](ctx context.Context, c *Client, path string) ([]T, error) {
+	return PaginateEnvelope(ctx, c, path, func(page []T) []T { return page })
+}
+
+// PaginateEnvelope fetches every page of a list endpoint whose pages are
+// decoded into E, using items to extract the page elements.
+// It stops on an empty or short page, or when the endpoint ignores the
+// pagination parameters and returns the same page again.
+func PaginateEnvelope[E any, T any](ctx context.Context, c *Client, path string, items func(E) []T) ([]T, error) {
+	sep := "?"
+	if strings.Contains(path, "?") {
+		sep = "&"
+	}
+
+	var all []T
+	var previous json.RawMessage
+	params := PaginationParams{PageSize: defaultPageSize, PageNumber: 1}
+	for ; params.PageNumber <= maxPages; params.PageNumber++ {
+		var raw json.RawMessage
+		if err := c.doRequest(ctx, "GET", path+sep+params.query(), nil, &raw); err != nil {
+			return nil, err
+		}
+		if previous != nil && bytes.Equal(raw, previous) {
+			return all, nil
+		}
+		previous = raw
+
+		var envelope E
+		if len(raw) > 0 {
+			if err := json.Unmarshal(raw, &envelope); err != nil {
+				return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+			}
+		}
+		page := items(envelope)
+		all = append(all, page...)
+		if len(page) < params.PageSize {
+			return all, nil
+		}
+	}
+	return nil, fmt.Errorf("pagination of %s exceeded %d pages", path, maxPages)
+}
@@ -0,0 +1,74 @@
+package client
+
+import (
+	"context"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"golang.org/x/time/rate"
+)
+
+// rateLimiter throttles requests with a token bucket and caps how many
+// requests are in flight at once. A single limiter is shared by every
+// resource and data source using the same Client.
+type rateLimiter struct {
+	limiter  *rate.Limiter
+	inFlight chan struct{}
+}
+
+func newRateLimiter(requestsPerSecond float64, burst int) *rateLimiter {
+	if burst < 1 {
+		burst = 1
+	}
+	return &rateLimiter{
+		limiter:  rate.NewLimiter(rate.Limit(requestsPerSecond), burst),
+		inFlight: make(chan struct{}, burst),
+	}
+}
+
+// acquire blocks until the request may be sent. Every successful acquire
+// must be paired with a release once the response has been read.
+func (l *rateLimiter) acquire(ctx context.Context, method, path string) error {
+	select {
+	case l.inFlight <- struct{}{}:
+	default:
+		tflog.Debug(ctx, "Waiting for an in-flight Domotz API request slot", map[string]interface{}{
+			"method":    method,
+			"path":      path,
+			"in_flight": cap(l.inFlight),
+		})
+		select {
+		case l.inFlight <- struct{}{}:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	reservation := l.limiter.Reserve()
+	delay := reservation.Delay()
+	if delay == 0 {
+		return nil
+	}
+
+	tflog.Debug(ctx, "Throttling Domotz API request", map[string]interface{}{
+		"method": method,
+		"path":   path,
+		"delay":  delay.String(),
+	})
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		reservation.Cancel()
+		l.release()
+		return ctx.Err()
+	}
+}
+
+// release frees the in-flight slot taken by acquire
+func (l *rateLimiter) release() {
+	<-l.inFlight
+}
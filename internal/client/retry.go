@@ -0,0 +1,134 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+const (
+	defaultMaxRetries = 3
+	defaultMinBackoff = 1 * time.Second
+	defaultMaxBackoff = 30 * time.Second
+)
+
+// RetryPolicy controls how failed requests are retried
+type RetryPolicy struct {
+	MaxRetries    int           // Number of retries after the first attempt
+	MinBackoff    time.Duration // Base delay for the first retry
+	MaxBackoff    time.Duration // Upper bound for any computed delay
+	RetryOnStatus []int         // HTTP status codes that trigger a retry
+}
+
+// DefaultRetryPolicy returns the retry policy used when the provider does not override it
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxRetries: defaultMaxRetries,
+		MinBackoff: defaultMinBackoff,
+		MaxBackoff: defaultMaxBackoff,
+		RetryOnStatus: []int{
+			http.StatusTooManyRequests,
+			http.StatusBadGateway,
+			http.StatusServiceUnavailable,
+			http.StatusGatewayTimeout,
+		},
+	}
+}
+
+// shouldRetry checks if an error is retryable: a configured status code,
+// or a connection reset or transport timeout on an idempotent method. A POST
+// may already have been processed when the connection broke, so retrying it
+// could create a duplicate object. A Retry-After longer than MaxBackoff is
+// not waited for: the error is returned instead of stalling the run.
+func (p RetryPolicy) shouldRetry(method string, err error) bool {
+	if err == nil {
+		return false
+	}
+
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		if apiErr.RetryAfter > p.MaxBackoff {
+			return false
+		}
+		for _, status := range p.RetryOnStatus {
+			if apiErr.StatusCode == status {
+				return true
+			}
+		}
+		return false
+	}
+
+	// The caller's own cancellation or deadline is never retried
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	if !isIdempotent(method) {
+		return false
+	}
+
+	if errors.Is(err, syscall.ECONNRESET) || errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, io.EOF) {
+		return true
+	}
+
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+// isIdempotent reports whether repeating a request with method has the same
+// effect as sending it once
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// backoff returns the delay before the given retry attempt (starting at 1).
+// A Retry-After value sent by the server is used as is, since shouldRetry
+// has already refused any longer than MaxBackoff; otherwise the delay is
+// drawn uniformly from [0, min(MaxBackoff, MinBackoff*2^(attempt-1))]
+func (p RetryPolicy) backoff(attempt int, err error) time.Duration {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && apiErr.RetryAfter > 0 {
+		return apiErr.RetryAfter
+	}
+
+	ceiling := p.MaxBackoff
+	if shift := attempt - 1; shift < 32 {
+		if exp := p.MinBackoff << uint(shift); exp > 0 && exp < ceiling {
+			ceiling = exp
+		}
+	}
+	if ceiling <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int63n(int64(ceiling) + 1))
+}
+
+// parseRetryAfter parses a Retry-After header given either as delay seconds
+// or as an HTTP date. It returns zero when the header is absent or invalid.
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0
+		}
+		return time.Duration(seconds) * time.Second
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		if d := date.Sub(now); d > 0 {
+			return d
+		}
+	}
+	return 0
+}
@@ -17,14 +17,16 @@ func (c *Client) GetSNMPSensor(ctx context.Context, agentID, deviceID, sensorID
 			return &sensor, nil
 		}
 	}
-	return nil, fmt.Errorf("SNMP sensor with ID %d not found", sensorID)
+	return nil, &NotFoundError{
+		Message: fmt.Sprintf("SNMP sensor with ID %d not found", sensorID),
+	}
 }
 
-// ListSNMPSensors retrieves all SNMP sensors (Domotz Eyes) for a device
+// ListSNMPSensors retrieves all SNMP sensors (Domotz Eyes) for a device with pagination
 func (c *Client) ListSNMPSensors(ctx context.Context, agentID, deviceID int32) ([]SNMPSensor, error) {
 	path := fmt.Sprintf("/agent/%d/device/%d/eye/snmp", agentID, deviceID)
-	var sensors []SNMPSensor
-	if err := c.doRequest(ctx, "GET", path, nil, &sensors); err != nil {
+	sensors, err := Paginate[SNMPSensor](ctx, c, path)
+	if err != nil {
 		return nil, fmt.Errorf("failed to list SNMP sensors: %w", err)
 	}
 	return sensors, nil
@@ -71,14 +73,16 @@ func (c *Client) GetTCPSensor(ctx context.Context, agentID, deviceID, sensorID i
 			return &sensor, nil
 		}
 	}
-	return nil, fmt.Errorf("TCP sensor with ID %d not found", sensorID)
+	return nil, &NotFoundError{
+		Message: fmt.Sprintf("TCP sensor with ID %d not found", sensorID),
+	}
 }
 
-// ListTCPSensors retrieves all TCP sensors (Domotz Eyes) for a device
+// ListTCPSensors retrieves all TCP sensors (Domotz Eyes) for a device with pagination
 func (c *Client) ListTCPSensors(ctx context.Context, agentID, deviceID int32) ([]TCPSensor, error) {
 	path := fmt.Sprintf("/agent/%d/device/%d/eye/tcp", agentID, deviceID)
-	var sensors []TCPSensor
-	if err := c.doRequest(ctx, "GET", path, nil, &sensors); err != nil {
+	sensors, err := Paginate[TCPSensor](ctx, c, path)
+	if err != nil {
 		return nil, fmt.Errorf("failed to list TCP sensors: %w", err)
 	}
 	return sensors, nil
@@ -92,16 +96,27 @@ func (c *Client) CreateTCPSensor(ctx context.Context, agentID, deviceID int32, r
 		return nil, fmt.Errorf("failed to create TCP sensor: %w", err)
 	}
 	// API returns empty body, find created sensor by port
-	sensors, err := c.ListTCPSensors(ctx, agentID, deviceID)
+	sensor, err := c.GetTCPSensorByPort(ctx, agentID, deviceID, req.Port)
 	if err != nil {
 		return nil, fmt.Errorf("failed to find created sensor: %w", err)
 	}
+	return sensor, nil
+}
+
+// GetTCPSensorByPort retrieves the TCP sensor (Domotz Eye) monitoring a given port on a device
+func (c *Client) GetTCPSensorByPort(ctx context.Context, agentID, deviceID, port int32) (*TCPSensor, error) {
+	sensors, err := c.ListTCPSensors(ctx, agentID, deviceID)
+	if err != nil {
+		return nil, err
+	}
 	for _, s := range sensors {
-		if s.Port == req.Port {
+		if s.Port == port {
 			return &s, nil
 		}
 	}
-	return nil, fmt.Errorf("created sensor not found")
+	return nil, &NotFoundError{
+		Message: fmt.Sprintf("TCP sensor for port %d not found", port),
+	}
 }
 
 // DeleteTCPSensor deletes a TCP sensor (Domotz Eye)
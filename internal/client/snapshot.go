@@ -0,0 +1,128 @@
+package client
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"golang.org/x/sync/singleflight"
+)
+
+// deviceSnapshots holds one ListDevices result per agent so that GetDevice
+// can be served without a request per device. Devices mutated through the
+// client are removed from the snapshot and read individually afterwards.
+type deviceSnapshots struct {
+	group singleflight.Group
+
+	mu         sync.Mutex
+	agents     map[int32]map[int32]Device
+	generation uint64 // bumped on every invalidation to discard in-flight loads
+}
+
+func newDeviceSnapshots() *deviceSnapshots {
+	return &deviceSnapshots{
+		agents: make(map[int32]map[int32]Device),
+	}
+}
+
+// lookup returns the device from the agent snapshot, loading the snapshot on
+// first use. It reports false when the device is not in the snapshot or the
+// snapshot cannot be loaded, so the caller falls back to the single-device endpoint.
+func (s *deviceSnapshots) lookup(ctx context.Context, c *Client, agentID, deviceID int32) (*Device, bool) {
+	devices, err := s.load(ctx, c, agentID)
+	if err != nil {
+		tflog.Debug(ctx, "Falling back to single device read, agent snapshot unavailable", map[string]interface{}{
+			"agent_id": agentID,
+			"error":    err.Error(),
+		})
+		return nil, false
+	}
+
+	device, ok := devices[deviceID]
+	if !ok {
+		return nil, false
+	}
+	device.IPAddresses = append([]string(nil), device.IPAddresses...)
+	return &device, true
+}
+
+func (s *deviceSnapshots) load(ctx context.Context, c *Client, agentID int32) (map[int32]Device, error) {
+	s.mu.Lock()
+	devices, ok := s.agents[agentID]
+	generation := s.generation
+	s.mu.Unlock()
+	if ok {
+		return devices, nil
+	}
+
+	v, err := doShared(ctx, &s.group, strconv.Itoa(int(agentID)), func(ctx context.Context) (interface{}, error) {
+		list, err := c.ListDevices(ctx, agentID)
+		if err != nil {
+			return nil, err
+		}
+		devices := make(map[int32]Device, len(list))
+		for _, d := range list {
+			devices[d.ID] = d
+		}
+
+		s.mu.Lock()
+		if s.generation == generation {
+			s.agents[agentID] = devices
+		}
+		s.mu.Unlock()
+
+		tflog.Debug(ctx, "Loaded device snapshot", map[string]interface{}{
+			"agent_id": agentID,
+			"devices":  len(devices),
+		})
+		return devices, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.(map[int32]Device), nil
+}
+
+// invalidate drops whatever part of the snapshots a mutation of path touches:
+// a single device for /agent/{id}/device/{id}/..., the whole agent otherwise.
+// Snapshot maps are never modified in place, as readers hold them unlocked.
+func (s *deviceSnapshots) invalidate(path string) {
+	path, _, _ = strings.Cut(path, "?")
+	segments := strings.Split(strings.TrimPrefix(path, "/"), "/")
+	if len(segments) < 2 || segments[0] != "agent" {
+		return
+	}
+	agentID, err := strconv.ParseInt(segments[1], 10, 32)
+	if err != nil {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.generation++
+
+	if len(segments) >= 4 && segments[2] == "device" {
+		deviceID, err := strconv.ParseInt(segments[3], 10, 32)
+		if err != nil {
+			// e.g. POST /agent/{id}/device/external-host: new devices are
+			// snapshot misses and are read from the single-device endpoint
+			return
+		}
+		devices, ok := s.agents[int32(agentID)]
+		if !ok {
+			return
+		}
+		remaining := make(map[int32]Device, len(devices))
+		for id, d := range devices {
+			if id != int32(deviceID) {
+				remaining[id] = d
+			}
+		}
+		s.agents[int32(agentID)] = remaining
+		return
+	}
+
+	delete(s.agents, int32(agentID))
+}
@@ -0,0 +1,37 @@
+package client
+
+import (
+	"context"
+	"fmt"
+)
+
+// GetSNMPAuthentication retrieves the SNMP authentication settings of a device.
+// Communities and keys are not returned.
+func (c *Client) GetSNMPAuthentication(ctx context.Context, agentID, deviceID int32) (*SNMPAuthentication, error) {
+	path := fmt.Sprintf("/agent/%d/device/%d/snmp-authentication", agentID, deviceID)
+	var auth SNMPAuthentication
+	if err := c.doRequest(ctx, "GET", path, nil, &auth); err != nil {
+		return nil, fmt.Errorf("failed to get SNMP authentication: %w", err)
+	}
+	return &auth, nil
+}
+
+// SetSNMPAuthentication replaces the SNMP authentication settings of a device.
+// The collector then retries SNMP and updates the device's AuthenticationStatus.
+func (c *Client) SetSNMPAuthentication(ctx context.Context, agentID, deviceID int32, auth SNMPAuthentication) error {
+	path := fmt.Sprintf("/agent/%d/device/%d/snmp-authentication", agentID, deviceID)
+	if err := c.doRequestNoContent(ctx, "PUT", path, auth); err != nil {
+		return fmt.Errorf("failed to set SNMP authentication: %w", err)
+	}
+	return nil
+}
+
+// DeleteSNMPAuthentication removes the SNMP authentication settings of a
+// device, so the collector falls back to its default communities
+func (c *Client) DeleteSNMPAuthentication(ctx context.Context, agentID, deviceID int32) error {
+	path := fmt.Sprintf("/agent/%d/device/%d/snmp-authentication", agentID, deviceID)
+	if err := c.doRequestNoContent(ctx, "DELETE", path, nil); err != nil {
+		return fmt.Errorf("failed to delete SNMP authentication: %w", err)
+	}
+	return nil
+}
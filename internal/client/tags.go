@@ -17,17 +17,19 @@ func (c *Client) GetTag(ctx context.Context, tagID int32) (*Tag, error) {
 			return &tag, nil
 		}
 	}
-	return nil, fmt.Errorf("tag with ID %d not found", tagID)
+	return nil, &NotFoundError{
+		Message: fmt.Sprintf("tag with ID %d not found", tagID),
+	}
 }
 
-// ListTags retrieves all custom tags
+// ListTags retrieves all custom tags with pagination
 func (c *Client) ListTags(ctx context.Context) ([]Tag, error) {
 	path := "/custom-tag"
-	var response TagsResponse
-	if err := c.doRequest(ctx, "GET", path, nil, &response); err != nil {
+	tags, err := PaginateEnvelope(ctx, c, path, func(r TagsResponse) []Tag { return r.Tags })
+	if err != nil {
 		return nil, fmt.Errorf("failed to list tags: %w", err)
 	}
-	return response.Tags, nil
+	return tags, nil
 }
 
 // CreateTag creates a new custom tag
@@ -91,11 +93,11 @@ func (c *Client) UnbindTagFromDevice(ctx context.Context, agentID, deviceID, tag
 	return nil
 }
 
-// ListDeviceTags retrieves all custom tags associated with a device
+// ListDeviceTags retrieves all custom tags associated with a device with pagination
 func (c *Client) ListDeviceTags(ctx context.Context, agentID, deviceID int32) ([]Tag, error) {
 	path := fmt.Sprintf("/agent/%d/device/%d/custom-tag/binding", agentID, deviceID)
-	var tags []Tag
-	if err := c.doRequest(ctx, "GET", path, nil, &tags); err != nil {
+	tags, err := Paginate[Tag](ctx, c, path)
+	if err != nil {
 		return nil, fmt.Errorf("failed to list device tags: %w", err)
 	}
 	return tags, nil
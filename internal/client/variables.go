@@ -17,20 +17,10 @@ func (c *Client) GetVariable(ctx context.Context, agentID, deviceID, variableID
 
 // ListVariables retrieves all variables for a device with pagination
 func (c *Client) ListVariables(ctx context.Context, agentID, deviceID int32) ([]Variable, error) {
-	var allVariables []Variable
-	page := 1
-	for {
-		path := fmt.Sprintf("/agent/%d/device/%d/variable?page_size=%d&page_number=%d",
-			agentID, deviceID, defaultPageSize, page)
-		var variables []Variable
-		if err := c.doRequest(ctx, "GET", path, nil, &variables); err != nil {
-			return nil, fmt.Errorf("failed to list variables: %w", err)
-		}
-		allVariables = append(allVariables, variables...)
-		if len(variables) < defaultPageSize {
-			break
-		}
-		page++
+	path := fmt.Sprintf("/agent/%d/device/%d/variable", agentID, deviceID)
+	variables, err := Paginate[Variable](ctx, c, path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list variables: %w", err)
 	}
-	return allVariables, nil
+	return variables, nil
 }
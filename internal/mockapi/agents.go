@@ -0,0 +1,153 @@
+package mockapi
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/domotz/terraform-provider-domotz/internal/client"
+)
+
+// AddAgent seeds an agent (collector). Agents cannot be created through the API.
+// The agent's team, if any, is registered as well.
+func (s *Server) AddAgent(agent client.Agent) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.agents[agent.ID] = agent
+	if agent.Team.ID != 0 {
+		s.teams[agent.Team.ID] = agent.Team
+	}
+}
+
+// AddTeam seeds a team that agents can be moved to
+func (s *Server) AddTeam(team client.Team) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.teams[team.ID] = team
+}
+
+// Agent returns the agent with the given ID, if any
+func (s *Server) Agent(agentID int32) (client.Agent, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	agent, ok := s.agents[agentID]
+	return agent, ok
+}
+
+// UpdateAgent replaces an agent out of band
+func (s *Server) UpdateAgent(agent client.Agent) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.agents[agent.ID] = agent
+}
+
+func (s *Server) handleAgents(w http.ResponseWriter, r *http.Request, rt route) bool {
+	if _, ok := rt.match("agent"); ok && r.Method == http.MethodGet {
+		agents := make([]client.Agent, 0, len(s.agents))
+		for _, id := range sortedKeys(s.agents) {
+			agents = append(agents, s.agents[id])
+		}
+		writeJSON(w, paginate(r, agents))
+		return true
+	}
+
+	for _, field := range []string{"display_name", "location", "timezone", "team"} {
+		if ids, ok := rt.match("agent", "{id}", field); ok {
+			return s.handleAgentField(w, r, ids[0], field)
+		}
+	}
+
+	ids, ok := rt.match("agent", "{id}")
+	if !ok {
+		return false
+	}
+	agent, exists := s.agents[ids[0]]
+	if !exists {
+		notFound(w, "agent", ids[0])
+		return true
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, agent)
+	case http.MethodDelete:
+		delete(s.agents, agent.ID)
+		delete(s.agentAlertProfiles, agent.ID)
+		for key := range s.devices {
+			if key.AgentID == agent.ID {
+				s.deleteDevice(key)
+			}
+		}
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		return false
+	}
+	return true
+}
+
+// handleAgentField updates one setting of an agent
+func (s *Server) handleAgentField(w http.ResponseWriter, r *http.Request, agentID int32, field string) bool {
+	agent, exists := s.agents[agentID]
+	if !exists {
+		notFound(w, "agent", agentID)
+		return true
+	}
+
+	switch {
+	case field == "display_name" && r.Method == http.MethodPut:
+		var displayName string
+		if !decodeBody(w, r, &displayName) {
+			return true
+		}
+		if displayName == "" {
+			writeError(w, http.StatusBadRequest, "display_name must not be empty")
+			return true
+		}
+		agent.DisplayName = displayName
+	case field == "location" && r.Method == http.MethodPut:
+		var location client.AgentLocation
+		if !decodeBody(w, r, &location) {
+			return true
+		}
+		if location.Latitude == "" || location.Longitude == "" {
+			writeError(w, http.StatusBadRequest, "latitude and longitude are required")
+			return true
+		}
+		agent.Location = &location
+	case field == "timezone" && r.Method == http.MethodPut:
+		var timeZone string
+		if !decodeBody(w, r, &timeZone) {
+			return true
+		}
+		if _, err := time.LoadLocation(timeZone); err != nil || timeZone == "" {
+			writeError(w, http.StatusBadRequest, "invalid timezone: "+timeZone)
+			return true
+		}
+		agent.TimeZone = timeZone
+	case field == "team" && r.Method == http.MethodPut:
+		var teamID int32
+		if !decodeBody(w, r, &teamID) {
+			return true
+		}
+		team, ok := s.teams[teamID]
+		if !ok {
+			notFound(w, "team", teamID)
+			return true
+		}
+		agent.Team = team
+	default:
+		return false
+	}
+
+	s.agents[agentID] = agent
+	w.WriteHeader(http.StatusNoContent)
+	return true
+}
+
+// agentExists answers 404 when the agent is unknown. Callers must hold s.mu.
+func (s *Server) agentExists(w http.ResponseWriter, agentID int32) bool {
+	if _, ok := s.agents[agentID]; !ok {
+		notFound(w, "agent", agentID)
+		return false
+	}
+	return true
+}
@@ -0,0 +1,313 @@
+package mockapi
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/domotz/terraform-provider-domotz/internal/client"
+)
+
+// AddAlertProfile seeds an alert profile. A zero ID is replaced by a fresh one.
+func (s *Server) AddAlertProfile(profile client.AlertProfile) client.AlertProfile {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if profile.ID == 0 {
+		profile.ID = s.newID()
+	}
+	s.alertProfiles[profile.ID] = profile
+	return profile
+}
+
+// AlertProfile returns the stored alert profile, if any
+func (s *Server) AlertProfile(profileID int32) (client.AlertProfile, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	profile, ok := s.alertProfiles[profileID]
+	return profile, ok
+}
+
+// UpdateAlertProfile replaces a stored alert profile out of band
+func (s *Server) UpdateAlertProfile(profile client.AlertProfile) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.alertProfiles[profile.ID] = profile
+}
+
+// DeleteAlertProfile removes an alert profile and its bindings out of band
+func (s *Server) DeleteAlertProfile(profileID int32) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.deleteAlertProfile(profileID)
+}
+
+// BindDeviceAlertProfile applies an alert profile to a device out of band
+func (s *Server) BindDeviceAlertProfile(agentID, deviceID, profileID int32) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	addBinding(s.deviceAlertProfiles, deviceKey{agentID, deviceID}, profileID)
+}
+
+// UnbindDeviceAlertProfile removes an alert profile from a device out of band
+func (s *Server) UnbindDeviceAlertProfile(agentID, deviceID, profileID int32) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.deviceAlertProfiles[deviceKey{agentID, deviceID}], profileID)
+}
+
+// DeviceAlertProfileIDs returns the IDs of the alert profiles applied to a device
+func (s *Server) DeviceAlertProfileIDs(agentID, deviceID int32) []int32 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return sortedKeys(s.deviceAlertProfiles[deviceKey{agentID, deviceID}])
+}
+
+// UnbindAgentAlertProfile removes an alert profile from an agent out of band
+func (s *Server) UnbindAgentAlertProfile(agentID, profileID int32) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.agentAlertProfiles[agentID], profileID)
+}
+
+// AgentAlertProfileIDs returns the IDs of the alert profiles applied to an agent
+func (s *Server) AgentAlertProfileIDs(agentID int32) []int32 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return sortedKeys(s.agentAlertProfiles[agentID])
+}
+
+// deleteAlertProfile removes a profile and its bindings. Callers must hold s.mu.
+func (s *Server) deleteAlertProfile(profileID int32) {
+	delete(s.alertProfiles, profileID)
+	for _, profiles := range s.deviceAlertProfiles {
+		delete(profiles, profileID)
+	}
+	for _, profiles := range s.agentAlertProfiles {
+		delete(profiles, profileID)
+	}
+	delete(s.profileContacts, profileID)
+}
+
+// addBinding records a binding in m. Callers must hold s.mu.
+func addBinding[K comparable](m map[K]map[int32]bool, key K, id int32) {
+	if m[key] == nil {
+		m[key] = make(map[int32]bool)
+	}
+	m[key][id] = true
+}
+
+// validAlertEvents answers 400 unless every event is a known type
+func validAlertEvents(w http.ResponseWriter, events []string) bool {
+	if len(events) == 0 {
+		writeError(w, http.StatusBadRequest, "events must not be empty")
+		return false
+	}
+	for _, e := range events {
+		known := false
+		for _, k := range client.AlertProfileEvents {
+			if e == k {
+				known = true
+				break
+			}
+		}
+		if !known {
+			writeError(w, http.StatusBadRequest, "unknown event "+e+", expected one of "+strings.Join(client.AlertProfileEvents, ", "))
+			return false
+		}
+	}
+	return true
+}
+
+// validAlertThresholds answers 400 unless every threshold names a variable
+// and a known operator, and the events include device_variable_threshold
+func validAlertThresholds(w http.ResponseWriter, events []string, thresholds []client.AlertProfileThreshold) bool {
+	if len(thresholds) == 0 {
+		return true
+	}
+	subscribed := false
+	for _, e := range events {
+		if e == client.AlertEventDeviceVariableThreshold {
+			subscribed = true
+			break
+		}
+	}
+	if !subscribed {
+		writeError(w, http.StatusBadRequest, "thresholds require the "+client.AlertEventDeviceVariableThreshold+" event")
+		return false
+	}
+	for _, th := range thresholds {
+		if th.Variable == "" {
+			writeError(w, http.StatusBadRequest, "threshold variable is required")
+			return false
+		}
+		known := false
+		for _, op := range client.AlertThresholdOperators {
+			if th.Operator == op {
+				known = true
+				break
+			}
+		}
+		if !known {
+			writeError(w, http.StatusBadRequest, "unknown threshold operator "+th.Operator+", expected one of "+strings.Join(client.AlertThresholdOperators, ", "))
+			return false
+		}
+	}
+	return true
+}
+
+// profilesOf returns the alert profiles with the given IDs. Callers must hold s.mu.
+func (s *Server) profilesOf(ids map[int32]bool) []client.AlertProfile {
+	profiles := []client.AlertProfile{}
+	for _, id := range sortedKeys(ids) {
+		profiles = append(profiles, s.alertProfiles[id])
+	}
+	return profiles
+}
+
+func (s *Server) handleAlertProfiles(w http.ResponseWriter, r *http.Request, rt route) bool {
+	if _, ok := rt.match("alert-profile"); ok {
+		switch r.Method {
+		case http.MethodGet:
+			profiles := make([]client.AlertProfile, 0, len(s.alertProfiles))
+			for _, id := range sortedKeys(s.alertProfiles) {
+				profiles = append(profiles, s.alertProfiles[id])
+			}
+			writeJSON(w, paginate(r, profiles))
+		case http.MethodPost:
+			var req client.CreateAlertProfileRequest
+			if !decodeBody(w, r, &req) {
+				return true
+			}
+			if req.Name == "" {
+				writeError(w, http.StatusBadRequest, "name is required")
+				return true
+			}
+			if !validAlertEvents(w, req.Events) || !validAlertThresholds(w, req.Events, req.Thresholds) {
+				return true
+			}
+			profile := client.AlertProfile{
+				ID:          s.newID(),
+				Name:        req.Name,
+				Description: req.Description,
+				Events:      req.Events,
+				Thresholds:  req.Thresholds,
+			}
+			s.alertProfiles[profile.ID] = profile
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusCreated)
+			writeJSON(w, profile)
+		default:
+			return false
+		}
+		return true
+	}
+
+	if ids, ok := rt.match("alert-profile", "{id}"); ok {
+		profile, exists := s.alertProfiles[ids[0]]
+		if !exists {
+			notFound(w, "alert profile", ids[0])
+			return true
+		}
+		switch r.Method {
+		case http.MethodGet:
+			writeJSON(w, profile)
+		case http.MethodPut:
+			var req client.UpdateAlertProfileRequest
+			if !decodeBody(w, r, &req) {
+				return true
+			}
+			if req.Name != nil {
+				profile.Name = *req.Name
+			}
+			if req.Description != nil {
+				profile.Description = *req.Description
+			}
+			if req.Events != nil {
+				if !validAlertEvents(w, req.Events) {
+					return true
+				}
+				profile.Events = req.Events
+			}
+			if req.Thresholds != nil {
+				profile.Thresholds = req.Thresholds
+			}
+			if !validAlertThresholds(w, profile.Events, profile.Thresholds) {
+				return true
+			}
+			if len(profile.Thresholds) == 0 {
+				profile.Thresholds = nil
+			}
+			s.alertProfiles[profile.ID] = profile
+			w.WriteHeader(http.StatusNoContent)
+		case http.MethodDelete:
+			s.deleteAlertProfile(profile.ID)
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			return false
+		}
+		return true
+	}
+
+	if ids, ok := rt.match("agent", "{id}", "device", "{id}", "alert-profile", "binding"); ok && r.Method == http.MethodGet {
+		key := deviceKey{ids[0], ids[1]}
+		if _, exists := s.devices[key]; !exists {
+			notFound(w, "device", ids[1])
+			return true
+		}
+		writeJSON(w, paginate(r, s.profilesOf(s.deviceAlertProfiles[key])))
+		return true
+	}
+
+	if ids, ok := rt.match("agent", "{id}", "device", "{id}", "alert-profile", "{id}", "binding"); ok {
+		key := deviceKey{ids[0], ids[1]}
+		if _, exists := s.devices[key]; !exists {
+			notFound(w, "device", ids[1])
+			return true
+		}
+		return s.handleAlertProfileBinding(w, r, s.deviceAlertProfiles[key], ids[2], func() {
+			addBinding(s.deviceAlertProfiles, key, ids[2])
+		})
+	}
+
+	if ids, ok := rt.match("agent", "{id}", "alert-profile", "binding"); ok && r.Method == http.MethodGet {
+		if !s.agentExists(w, ids[0]) {
+			return true
+		}
+		writeJSON(w, paginate(r, s.profilesOf(s.agentAlertProfiles[ids[0]])))
+		return true
+	}
+
+	if ids, ok := rt.match("agent", "{id}", "alert-profile", "{id}", "binding"); ok {
+		if !s.agentExists(w, ids[0]) {
+			return true
+		}
+		return s.handleAlertProfileBinding(w, r, s.agentAlertProfiles[ids[0]], ids[1], func() {
+			addBinding(s.agentAlertProfiles, ids[0], ids[1])
+		})
+	}
+
+	return false
+}
+
+// handleAlertProfileBinding serves POST and DELETE on a binding, where bound
+// holds the profiles currently applied to the target. Callers must hold s.mu.
+func (s *Server) handleAlertProfileBinding(w http.ResponseWriter, r *http.Request, bound map[int32]bool, profileID int32, bind func()) bool {
+	if _, exists := s.alertProfiles[profileID]; !exists {
+		notFound(w, "alert profile", profileID)
+		return true
+	}
+	switch r.Method {
+	case http.MethodPost:
+		bind()
+		w.WriteHeader(http.StatusNoContent)
+	case http.MethodDelete:
+		if !bound[profileID] {
+			notFound(w, "binding for alert profile", profileID)
+			return true
+		}
+		delete(bound, profileID)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		return false
+	}
+	return true
+}
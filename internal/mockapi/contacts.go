@@ -0,0 +1,157 @@
+package mockapi
+
+import (
+	"net/http"
+	"net/url"
+
+	"github.com/domotz/terraform-provider-domotz/internal/client"
+)
+
+// AddWebhookContact seeds a webhook contact. A zero ID is replaced by a fresh one.
+func (s *Server) AddWebhookContact(contact client.WebhookContact) client.WebhookContact {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if contact.ID == 0 {
+		contact.ID = s.newID()
+	}
+	s.contacts[contact.ID] = contact
+	return contact
+}
+
+// WebhookContact returns the stored webhook contact, if any
+func (s *Server) WebhookContact(contactID int32) (client.WebhookContact, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	contact, ok := s.contacts[contactID]
+	return contact, ok
+}
+
+// UpdateWebhookContact replaces a stored webhook contact out of band
+func (s *Server) UpdateWebhookContact(contact client.WebhookContact) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.contacts[contact.ID] = contact
+}
+
+// UnbindAlertProfileContact removes a contact from an alert profile out of band
+func (s *Server) UnbindAlertProfileContact(profileID, contactID int32) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.profileContacts[profileID], contactID)
+}
+
+// AlertProfileContactIDs returns the IDs of the contacts an alert profile delivers to
+func (s *Server) AlertProfileContactIDs(profileID int32) []int32 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return sortedKeys(s.profileContacts[profileID])
+}
+
+// validContact answers 400 unless the request has a name and an http(s) URL
+func validContact(w http.ResponseWriter, req client.WebhookContactRequest) bool {
+	if req.Name == "" {
+		writeError(w, http.StatusBadRequest, "name is required")
+		return false
+	}
+	u, err := url.Parse(req.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		writeError(w, http.StatusBadRequest, "url must be an absolute http or https URL")
+		return false
+	}
+	return true
+}
+
+func (s *Server) handleContacts(w http.ResponseWriter, r *http.Request, rt route) bool {
+	if _, ok := rt.match("contact", "webhook"); ok {
+		switch r.Method {
+		case http.MethodGet:
+			contacts := make([]client.WebhookContact, 0, len(s.contacts))
+			for _, id := range sortedKeys(s.contacts) {
+				contacts = append(contacts, s.contacts[id])
+			}
+			writeJSON(w, paginate(r, contacts))
+		case http.MethodPost:
+			var req client.WebhookContactRequest
+			if !decodeBody(w, r, &req) || !validContact(w, req) {
+				return true
+			}
+			contact := client.WebhookContact{ID: s.newID(), Name: req.Name, URL: req.URL, Headers: req.Headers}
+			s.contacts[contact.ID] = contact
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusCreated)
+			writeJSON(w, contact)
+		default:
+			return false
+		}
+		return true
+	}
+
+	if ids, ok := rt.match("contact", "webhook", "{id}"); ok {
+		contact, exists := s.contacts[ids[0]]
+		if !exists {
+			notFound(w, "contact", ids[0])
+			return true
+		}
+		switch r.Method {
+		case http.MethodGet:
+			writeJSON(w, contact)
+		case http.MethodPut:
+			var req client.WebhookContactRequest
+			if !decodeBody(w, r, &req) || !validContact(w, req) {
+				return true
+			}
+			s.contacts[contact.ID] = client.WebhookContact{ID: contact.ID, Name: req.Name, URL: req.URL, Headers: req.Headers}
+			w.WriteHeader(http.StatusNoContent)
+		case http.MethodDelete:
+			delete(s.contacts, contact.ID)
+			for _, contacts := range s.profileContacts {
+				delete(contacts, contact.ID)
+			}
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			return false
+		}
+		return true
+	}
+
+	if ids, ok := rt.match("alert-profile", "{id}", "contact", "binding"); ok && r.Method == http.MethodGet {
+		if _, exists := s.alertProfiles[ids[0]]; !exists {
+			notFound(w, "alert profile", ids[0])
+			return true
+		}
+		contacts := []client.WebhookContact{}
+		for _, id := range sortedKeys(s.profileContacts[ids[0]]) {
+			contacts = append(contacts, s.contacts[id])
+		}
+		writeJSON(w, paginate(r, contacts))
+		return true
+	}
+
+	if ids, ok := rt.match("alert-profile", "{id}", "contact", "{id}", "binding"); ok {
+		if _, exists := s.alertProfiles[ids[0]]; !exists {
+			notFound(w, "alert profile", ids[0])
+			return true
+		}
+		if _, exists := s.contacts[ids[1]]; !exists {
+			notFound(w, "contact", ids[1])
+			return true
+		}
+		switch r.Method {
+		case http.MethodPost:
+			addBinding(s.profileContacts, ids[0], ids[1])
+			w.WriteHeader(http.StatusNoContent)
+		case http.MethodDelete:
+			if !s.profileContacts[ids[0]][ids[1]] {
+				notFound(w, "binding for contact", ids[1])
+				return true
+			}
+			delete(s.profileContacts[ids[0]], ids[1])
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			return false
+		}
+		return true
+	}
+
+	return false
+}
@@ -0,0 +1,242 @@
+package mockapi
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/domotz/terraform-provider-domotz/internal/client"
+)
+
+// customDriverSamplePeriods are the sample periods, in seconds, the API accepts
+var customDriverSamplePeriods = []int32{60, 300, 600, 900, 1800, 3600, 7200, 14400, 43200, 86400}
+
+// customDriverValueTypes are the parameter value types the API accepts
+var customDriverValueTypes = []string{"STRING", "NUMBER", "LIST", "SECRET_TEXT"}
+
+// AddCustomDriver seeds a custom driver. A zero ID is replaced by a fresh one.
+func (s *Server) AddCustomDriver(driver client.CustomDriver) client.CustomDriver {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if driver.ID == 0 {
+		driver.ID = s.newID()
+	}
+	s.customDrivers[driver.ID] = driver
+	return driver
+}
+
+// CustomDriver returns the stored custom driver, if any
+func (s *Server) CustomDriver(driverID int32) (client.CustomDriver, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	driver, ok := s.customDrivers[driverID]
+	return driver, ok
+}
+
+// UpdateCustomDriver replaces a stored custom driver out of band
+func (s *Server) UpdateCustomDriver(driver client.CustomDriver) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.customDrivers[driver.ID] = driver
+}
+
+// CustomDriverAssociation returns the stored association and the credentials
+// it was created with, if any
+func (s *Server) CustomDriverAssociation(associationID int32) (client.CustomDriverAssociation, *client.CustomDriverCredentials, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	association, ok := s.driverAssociations[associationID]
+	return association, s.driverCredentials[associationID], ok
+}
+
+// DeleteCustomDriverAssociation removes an association out of band
+func (s *Server) DeleteCustomDriverAssociation(associationID int32) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.deleteDriverAssociation(associationID)
+}
+
+// deleteDriverAssociation removes an association. Callers must hold s.mu.
+func (s *Server) deleteDriverAssociation(associationID int32) {
+	delete(s.driverAssociations, associationID)
+	delete(s.driverCredentials, associationID)
+}
+
+// validCustomDriver answers 400 unless the request is a well-formed driver
+func validCustomDriver(w http.ResponseWriter, req client.CustomDriverRequest) bool {
+	if req.Name == "" || req.Script == "" {
+		writeError(w, http.StatusBadRequest, "name and script are required")
+		return false
+	}
+	if !containsValue(customDriverSamplePeriods, req.MinimalSamplePeriod) {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("unsupported minimal_sample_period %d", req.MinimalSamplePeriod))
+		return false
+	}
+	seen := make(map[string]bool)
+	for _, p := range req.Parameters {
+		if p.Name == "" || seen[p.Name] {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("parameter names must be unique and non-empty, got %q", p.Name))
+			return false
+		}
+		seen[p.Name] = true
+		if !containsValue(customDriverValueTypes, p.ValueType) {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("unsupported value_type %q", p.ValueType))
+			return false
+		}
+	}
+	return true
+}
+
+func customDriverFrom(id int32, req client.CustomDriverRequest) client.CustomDriver {
+	return client.CustomDriver{
+		ID:                  id,
+		Name:                req.Name,
+		Description:         req.Description,
+		Script:              req.Script,
+		MinimalSamplePeriod: req.MinimalSamplePeriod,
+		Parameters:          req.Parameters,
+	}
+}
+
+func containsValue[T comparable](values []T, v T) bool {
+	for _, candidate := range values {
+		if candidate == v {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Server) handleCustomDrivers(w http.ResponseWriter, r *http.Request, rt route) bool {
+	if _, ok := rt.match("custom-driver"); ok {
+		switch r.Method {
+		case http.MethodGet:
+			drivers := make([]client.CustomDriver, 0, len(s.customDrivers))
+			for _, id := range sortedKeys(s.customDrivers) {
+				drivers = append(drivers, s.customDrivers[id])
+			}
+			writeJSON(w, paginate(r, drivers))
+		case http.MethodPost:
+			var req client.CustomDriverRequest
+			if !decodeBody(w, r, &req) || !validCustomDriver(w, req) {
+				return true
+			}
+			driver := customDriverFrom(s.newID(), req)
+			s.customDrivers[driver.ID] = driver
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusCreated)
+			writeJSON(w, driver)
+		default:
+			return false
+		}
+		return true
+	}
+
+	if _, ok := rt.match("custom-driver", "association"); ok && r.Method == http.MethodGet {
+		associations := make([]client.CustomDriverAssociation, 0, len(s.driverAssociations))
+		for _, id := range sortedKeys(s.driverAssociations) {
+			associations = append(associations, s.driverAssociations[id])
+		}
+		writeJSON(w, paginate(r, associations))
+		return true
+	}
+
+	if ids, ok := rt.match("custom-driver", "association", "{id}"); ok {
+		association, exists := s.driverAssociations[ids[0]]
+		if !exists {
+			notFound(w, "custom driver association", ids[0])
+			return true
+		}
+		switch r.Method {
+		case http.MethodGet:
+			writeJSON(w, association)
+		case http.MethodDelete:
+			s.deleteDriverAssociation(association.ID)
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			return false
+		}
+		return true
+	}
+
+	if ids, ok := rt.match("custom-driver", "{id}"); ok {
+		driver, exists := s.customDrivers[ids[0]]
+		if !exists {
+			notFound(w, "custom driver", ids[0])
+			return true
+		}
+		switch r.Method {
+		case http.MethodGet:
+			writeJSON(w, driver)
+		case http.MethodPut:
+			var req client.CustomDriverRequest
+			if !decodeBody(w, r, &req) || !validCustomDriver(w, req) {
+				return true
+			}
+			s.customDrivers[driver.ID] = customDriverFrom(driver.ID, req)
+			w.WriteHeader(http.StatusNoContent)
+		case http.MethodDelete:
+			delete(s.customDrivers, driver.ID)
+			for id, a := range s.driverAssociations {
+				if a.CustomDriverID == driver.ID {
+					s.deleteDriverAssociation(id)
+				}
+			}
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			return false
+		}
+		return true
+	}
+
+	if ids, ok := rt.match("custom-driver", "{id}", "agent", "{id}", "device", "{id}", "association"); ok && r.Method == http.MethodPost {
+		driver, exists := s.customDrivers[ids[0]]
+		if !exists {
+			notFound(w, "custom driver", ids[0])
+			return true
+		}
+		if _, exists := s.devices[deviceKey{ids[1], ids[2]}]; !exists {
+			notFound(w, "device", ids[2])
+			return true
+		}
+		var req client.CreateCustomDriverAssociationRequest
+		if !decodeBody(w, r, &req) {
+			return true
+		}
+		if req.SamplePeriod == 0 {
+			req.SamplePeriod = driver.MinimalSamplePeriod
+		}
+		if !containsValue(customDriverSamplePeriods, req.SamplePeriod) || req.SamplePeriod < driver.MinimalSamplePeriod {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("sample_period must be a supported period of at least %d seconds", driver.MinimalSamplePeriod))
+			return true
+		}
+		for _, p := range req.Parameters {
+			declared := false
+			for _, d := range driver.Parameters {
+				if d.Name == p.Name {
+					declared = true
+					break
+				}
+			}
+			if !declared {
+				writeError(w, http.StatusBadRequest, fmt.Sprintf("custom driver %d has no parameter %q", driver.ID, p.Name))
+				return true
+			}
+		}
+		association := client.CustomDriverAssociation{
+			ID:             s.newID(),
+			CustomDriverID: driver.ID,
+			AgentID:        ids[1],
+			DeviceID:       ids[2],
+			SamplePeriod:   req.SamplePeriod,
+			Parameters:     req.Parameters,
+		}
+		s.driverAssociations[association.ID] = association
+		s.driverCredentials[association.ID] = req.Credentials
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		writeJSON(w, association)
+		return true
+	}
+
+	return false
+}
@@ -0,0 +1,278 @@
+package mockapi
+
+import (
+	"net/http"
+	"net/url"
+	"sort"
+	"time"
+
+	"github.com/domotz/terraform-provider-domotz/internal/client"
+)
+
+// AddDevice seeds a device, e.g. one discovered by a collector. A zero ID is
+// replaced by a fresh one. The stored device is returned.
+func (s *Server) AddDevice(device client.Device) client.Device {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if device.ID == 0 {
+		device.ID = s.newID()
+	}
+	s.devices[deviceKey{device.AgentID, device.ID}] = device
+	return device
+}
+
+// Device returns the stored device, if any
+func (s *Server) Device(agentID, deviceID int32) (client.Device, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	device, ok := s.devices[deviceKey{agentID, deviceID}]
+	return device, ok
+}
+
+// DeleteDevice removes a device and everything attached to it, as if it
+// had been deleted outside of Terraform
+func (s *Server) DeleteDevice(agentID, deviceID int32) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.deleteDevice(deviceKey{agentID, deviceID})
+}
+
+// deleteDevice removes a device and its sensors, bindings and variables.
+// Callers must hold s.mu.
+func (s *Server) deleteDevice(key deviceKey) {
+	delete(s.devices, key)
+	delete(s.bindings, key)
+	delete(s.snmp, key)
+	delete(s.tcp, key)
+	delete(s.variables, key)
+	delete(s.deviceAlertProfiles, key)
+	delete(s.snmpAuth, key)
+	for id, a := range s.driverAssociations {
+		if a.AgentID == key.AgentID && a.DeviceID == key.DeviceID {
+			s.deleteDriverAssociation(id)
+		}
+	}
+}
+
+func (s *Server) handleDevices(w http.ResponseWriter, r *http.Request, rt route) bool {
+	if ids, ok := rt.match("agent", "{id}", "device"); ok && r.Method == http.MethodGet {
+		if !s.agentExists(w, ids[0]) {
+			return true
+		}
+		query := r.URL.Query()
+		devices := []client.Device{}
+		for key, d := range s.devices {
+			if key.AgentID != ids[0] ||
+				!matchesQuery(query, "importance", d.Importance) ||
+				!matchesQuery(query, "protocol", d.Protocol) ||
+				!matchesQuery(query, "status", d.Status) {
+				continue
+			}
+			devices = append(devices, d)
+		}
+		sort.Slice(devices, func(i, j int) bool { return devices[i].ID < devices[j].ID })
+		writeJSON(w, paginate(r, devices))
+		return true
+	}
+
+	if ids, ok := rt.match("agent", "{id}", "device", "external-host"); ok && r.Method == http.MethodPost {
+		if !s.agentExists(w, ids[0]) {
+			return true
+		}
+		var req client.CreateDeviceRequest
+		if !decodeBody(w, r, &req) {
+			return true
+		}
+		if req.DisplayName == "" || len(req.IPAddresses) == 0 {
+			writeError(w, http.StatusBadRequest, "display_name and ip_addresses are required")
+			return true
+		}
+		importance := req.Importance
+		if importance == "" {
+			importance = "FLOATING"
+		}
+		device := client.Device{
+			ID:          s.newID(),
+			AgentID:     ids[0],
+			DisplayName: req.DisplayName,
+			Protocol:    "IP",
+			IPAddresses: req.IPAddresses,
+			UserData:    req.UserData,
+			Importance:  importance,
+			Status:      "ONLINE",
+			FirstSeenAt: time.Now().UTC().Truncate(time.Second),
+		}
+		s.devices[deviceKey{device.AgentID, device.ID}] = device
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		writeJSON(w, device)
+		return true
+	}
+
+	if ids, ok := rt.match("agent", "{id}", "device", "{id}"); ok {
+		key := deviceKey{ids[0], ids[1]}
+		device, exists := s.devices[key]
+		if !exists {
+			notFound(w, "device", ids[1])
+			return true
+		}
+		switch r.Method {
+		case http.MethodGet:
+			writeJSON(w, device)
+		case http.MethodDelete:
+			s.deleteDevice(key)
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			return false
+		}
+		return true
+	}
+
+	if ids, ok := rt.match("agent", "{id}", "device", "{id}", "display_name"); ok && r.Method == http.MethodPut {
+		device, exists := s.devices[deviceKey{ids[0], ids[1]}]
+		if !exists {
+			notFound(w, "device", ids[1])
+			return true
+		}
+		var displayName string
+		if !decodeBody(w, r, &displayName) {
+			return true
+		}
+		if displayName == "" {
+			writeError(w, http.StatusBadRequest, "display_name must not be empty")
+			return true
+		}
+		device.DisplayName = displayName
+		s.devices[deviceKey{ids[0], ids[1]}] = device
+		w.WriteHeader(http.StatusNoContent)
+		return true
+	}
+
+	if ids, ok := rt.match("agent", "{id}", "device", "{id}", "importance"); ok && r.Method == http.MethodPut {
+		device, exists := s.devices[deviceKey{ids[0], ids[1]}]
+		if !exists {
+			notFound(w, "device", ids[1])
+			return true
+		}
+		var importance string
+		if !decodeBody(w, r, &importance) {
+			return true
+		}
+		if importance != "VITAL" && importance != "FLOATING" {
+			writeError(w, http.StatusBadRequest, "invalid importance: "+importance)
+			return true
+		}
+		device.Importance = importance
+		s.devices[deviceKey{ids[0], ids[1]}] = device
+		w.WriteHeader(http.StatusNoContent)
+		return true
+	}
+
+	if ids, ok := rt.match("agent", "{id}", "device", "{id}", "details", "zone"); ok {
+		key := deviceKey{ids[0], ids[1]}
+		device, exists := s.devices[key]
+		if !exists {
+			notFound(w, "device", ids[1])
+			return true
+		}
+		switch r.Method {
+		case http.MethodPut:
+			if !decodeBody(w, r, &device.Details.Zone) {
+				return true
+			}
+		case http.MethodDelete:
+			device.Details.Zone = ""
+		default:
+			return false
+		}
+		s.devices[key] = device
+		w.WriteHeader(http.StatusNoContent)
+		return true
+	}
+
+	if len(rt.segments) == 6 && rt.segments[4] == "user_data" {
+		ids, ok := rt.match("agent", "{id}", "device", "{id}", "user_data", rt.segments[5])
+		if !ok {
+			return false
+		}
+		switch r.Method {
+		case http.MethodPut:
+			return s.putUserData(w, r, deviceKey{ids[0], ids[1]}, rt.segments[5])
+		case http.MethodDelete:
+			return s.deleteUserData(w, deviceKey{ids[0], ids[1]}, rt.segments[5])
+		}
+		return false
+	}
+
+	return false
+}
+
+// putUserData updates a single user_data field. Callers must hold s.mu.
+func (s *Server) putUserData(w http.ResponseWriter, r *http.Request, key deviceKey, field string) bool {
+	device, exists := s.devices[key]
+	if !exists {
+		notFound(w, "device", key.DeviceID)
+		return true
+	}
+
+	switch field {
+	case "name", "model", "vendor":
+		var value string
+		if !decodeBody(w, r, &value) {
+			return true
+		}
+		switch field {
+		case "name":
+			device.UserData.Name = value
+		case "model":
+			device.UserData.Model = value
+		case "vendor":
+			device.UserData.Vendor = value
+		}
+	case "type":
+		var value int32
+		if !decodeBody(w, r, &value) {
+			return true
+		}
+		device.UserData.Type = value
+	default:
+		return false
+	}
+
+	s.devices[key] = device
+	w.WriteHeader(http.StatusNoContent)
+	return true
+}
+
+// deleteUserData resets a single user_data field. Callers must hold s.mu.
+func (s *Server) deleteUserData(w http.ResponseWriter, key deviceKey, field string) bool {
+	device, exists := s.devices[key]
+	if !exists {
+		notFound(w, "device", key.DeviceID)
+		return true
+	}
+
+	switch field {
+	case "name":
+		device.UserData.Name = ""
+	case "model":
+		device.UserData.Model = ""
+	case "vendor":
+		device.UserData.Vendor = ""
+	case "type":
+		device.UserData.Type = 0
+	default:
+		return false
+	}
+
+	s.devices[key] = device
+	w.WriteHeader(http.StatusNoContent)
+	return true
+}
+
+// matchesQuery reports whether value satisfies the query parameter key, which
+// matches everything when absent
+func matchesQuery(query url.Values, key, value string) bool {
+	want := query.Get(key)
+	return want == "" || want == value
+}
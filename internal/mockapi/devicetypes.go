@@ -0,0 +1,25 @@
+package mockapi
+
+import (
+	"net/http"
+
+	"github.com/domotz/terraform-provider-domotz/internal/client"
+)
+
+// DeviceTypes is the device type catalogue served by every Server
+var DeviceTypes = []client.DeviceType{
+	{ID: 1, Identifier: "UNKNOWN", Label: "Unknown"},
+	{ID: 2, Identifier: "COMPUTER", Label: "Computer", Capabilities: []string{"snmp", "wmi"}},
+	{ID: 7, Identifier: "ROUTER", Label: "Router", Vital: true, Capabilities: []string{"snmp", "ssh"}},
+	{ID: 12, Identifier: "SERVER", Label: "Server", Vital: true, Capabilities: []string{"snmp", "ssh", "wmi"}},
+	{ID: 15, Identifier: "SWITCH", Label: "Switch", Vital: true, Capabilities: []string{"snmp"}},
+	{ID: 24, Identifier: "PRINTER", Label: "Printer", Capabilities: []string{"snmp"}},
+}
+
+func (s *Server) handleDeviceTypes(w http.ResponseWriter, r *http.Request, rt route) bool {
+	if _, ok := rt.match("type", "device", "base"); ok && r.Method == http.MethodGet {
+		writeJSON(w, DeviceTypes)
+		return true
+	}
+	return false
+}
@@ -0,0 +1,163 @@
+package mockapi
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/domotz/terraform-provider-domotz/internal/client"
+)
+
+// AddTCPSensor seeds a TCP sensor on a device, e.g. one created in the
+// Domotz UI. A zero ID is replaced by a fresh one.
+func (s *Server) AddTCPSensor(agentID int32, sensor client.TCPSensor) client.TCPSensor {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if sensor.ID == 0 {
+		sensor.ID = s.newID()
+	}
+	key := deviceKey{agentID, sensor.DeviceID}
+	s.tcp[key] = append(s.tcp[key], sensor)
+	return sensor
+}
+
+// SNMPSensors returns the SNMP sensors of a device
+func (s *Server) SNMPSensors(agentID, deviceID int32) []client.SNMPSensor {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]client.SNMPSensor(nil), s.snmp[deviceKey{agentID, deviceID}]...)
+}
+
+// TCPSensors returns the TCP sensors of a device
+func (s *Server) TCPSensors(agentID, deviceID int32) []client.TCPSensor {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]client.TCPSensor(nil), s.tcp[deviceKey{agentID, deviceID}]...)
+}
+
+// DeleteSNMPSensor removes an SNMP sensor out of band
+func (s *Server) DeleteSNMPSensor(agentID, deviceID, sensorID int32) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key := deviceKey{agentID, deviceID}
+	s.snmp[key] = removeByID(s.snmp[key], sensorID, func(x client.SNMPSensor) int32 { return x.ID })
+}
+
+// DeleteTCPSensor removes a TCP sensor out of band
+func (s *Server) DeleteTCPSensor(agentID, deviceID, sensorID int32) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key := deviceKey{agentID, deviceID}
+	s.tcp[key] = removeByID(s.tcp[key], sensorID, func(x client.TCPSensor) int32 { return x.ID })
+}
+
+func (s *Server) handleSensors(w http.ResponseWriter, r *http.Request, rt route) bool {
+	if ids, ok := rt.match("agent", "{id}", "device", "{id}", "eye", "snmp"); ok {
+		key := deviceKey{ids[0], ids[1]}
+		if _, exists := s.devices[key]; !exists {
+			notFound(w, "device", ids[1])
+			return true
+		}
+		switch r.Method {
+		case http.MethodGet:
+			writeJSON(w, paginate(r, append([]client.SNMPSensor{}, s.snmp[key]...)))
+		case http.MethodPost:
+			var req client.CreateSNMPSensorRequest
+			if !decodeBody(w, r, &req) {
+				return true
+			}
+			if req.Name == "" || req.OID == "" {
+				writeError(w, http.StatusBadRequest, "name and oid are required")
+				return true
+			}
+			s.snmp[key] = append(s.snmp[key], client.SNMPSensor{
+				ID:        s.newID(),
+				AgentID:   key.AgentID,
+				DeviceID:  key.DeviceID,
+				Name:      req.Name,
+				OID:       req.OID,
+				Category:  req.Category,
+				ValueType: req.ValueType,
+			})
+			// The real API answers 201 without the created sensor
+			w.WriteHeader(http.StatusCreated)
+		default:
+			return false
+		}
+		return true
+	}
+
+	if ids, ok := rt.match("agent", "{id}", "device", "{id}", "eye", "snmp", "{id}"); ok && r.Method == http.MethodDelete {
+		key := deviceKey{ids[0], ids[1]}
+		remaining := removeByID(s.snmp[key], ids[2], func(x client.SNMPSensor) int32 { return x.ID })
+		if len(remaining) == len(s.snmp[key]) {
+			notFound(w, "SNMP sensor", ids[2])
+			return true
+		}
+		s.snmp[key] = remaining
+		w.WriteHeader(http.StatusNoContent)
+		return true
+	}
+
+	if ids, ok := rt.match("agent", "{id}", "device", "{id}", "eye", "tcp"); ok {
+		key := deviceKey{ids[0], ids[1]}
+		if _, exists := s.devices[key]; !exists {
+			notFound(w, "device", ids[1])
+			return true
+		}
+		switch r.Method {
+		case http.MethodGet:
+			writeJSON(w, paginate(r, append([]client.TCPSensor{}, s.tcp[key]...)))
+		case http.MethodPost:
+			var req client.CreateTCPSensorRequest
+			if !decodeBody(w, r, &req) {
+				return true
+			}
+			if req.Port < 1 || req.Port > 65535 {
+				writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid port %d", req.Port))
+				return true
+			}
+			for _, existing := range s.tcp[key] {
+				if existing.Port == req.Port {
+					writeError(w, http.StatusConflict, fmt.Sprintf("port %d is already monitored", req.Port))
+					return true
+				}
+			}
+			s.tcp[key] = append(s.tcp[key], client.TCPSensor{
+				ID:       s.newID(),
+				DeviceID: key.DeviceID,
+				Port:     req.Port,
+				Status:   "UP",
+			})
+			// The real API answers 201 without the created sensor
+			w.WriteHeader(http.StatusCreated)
+		default:
+			return false
+		}
+		return true
+	}
+
+	if ids, ok := rt.match("agent", "{id}", "device", "{id}", "eye", "tcp", "{id}"); ok && r.Method == http.MethodDelete {
+		key := deviceKey{ids[0], ids[1]}
+		remaining := removeByID(s.tcp[key], ids[2], func(x client.TCPSensor) int32 { return x.ID })
+		if len(remaining) == len(s.tcp[key]) {
+			notFound(w, "TCP sensor", ids[2])
+			return true
+		}
+		s.tcp[key] = remaining
+		w.WriteHeader(http.StatusNoContent)
+		return true
+	}
+
+	return false
+}
+
+// removeByID returns a copy of items without the element with the given ID
+func removeByID[T any](items []T, id int32, idOf func(T) int32) []T {
+	remaining := make([]T, 0, len(items))
+	for _, item := range items {
+		if idOf(item) != id {
+			remaining = append(remaining, item)
+		}
+	}
+	return remaining
+}
@@ -0,0 +1,226 @@
+// Package mockapi provides an in-memory stand-in for the Domotz public API,
+// served over httptest, for hermetic client and acceptance tests.
+//
+// It implements the endpoints used by internal/client and reproduces the
+// quirks of the real API: creates that answer 201/204 with an empty body,
+// 404s for unknown objects and 409 conflicts for duplicate TCP ports.
+package mockapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/domotz/terraform-provider-domotz/internal/client"
+)
+
+// APIKey is the key accepted by a Server unless overridden
+const APIKey = "mock-api-key"
+
+// deviceKey identifies a device across agents
+type deviceKey struct {
+	AgentID  int32
+	DeviceID int32
+}
+
+// Server is an in-memory Domotz API. All exported methods are safe for
+// concurrent use and may be called while requests are being served, e.g.
+// to delete objects out of band.
+type Server struct {
+	*httptest.Server
+
+	// APIKey is the value expected in the X-Api-Key header
+	APIKey string
+
+	mu                  sync.Mutex
+	nextID              int32
+	agents              map[int32]client.Agent
+	teams               map[int32]client.Team
+	devices             map[deviceKey]client.Device
+	tags                map[int32]client.Tag
+	bindings            map[deviceKey]map[int32]bool
+	snmp                map[deviceKey][]client.SNMPSensor
+	tcp                 map[deviceKey][]client.TCPSensor
+	variables           map[deviceKey][]client.Variable
+	alertProfiles       map[int32]client.AlertProfile
+	deviceAlertProfiles map[deviceKey]map[int32]bool
+	agentAlertProfiles  map[int32]map[int32]bool
+	contacts            map[int32]client.WebhookContact
+	profileContacts     map[int32]map[int32]bool
+	customDrivers       map[int32]client.CustomDriver
+	driverAssociations  map[int32]client.CustomDriverAssociation
+	driverCredentials   map[int32]*client.CustomDriverCredentials
+	snmpAuth            map[deviceKey]client.SNMPAuthentication
+	requests            map[string]int // "METHOD /path" -> count
+}
+
+// New starts a Server. The caller must Close it when done.
+func New() *Server {
+	s := &Server{
+		APIKey:              APIKey,
+		nextID:              1000,
+		agents:              make(map[int32]client.Agent),
+		teams:               make(map[int32]client.Team),
+		devices:             make(map[deviceKey]client.Device),
+		tags:                make(map[int32]client.Tag),
+		bindings:            make(map[deviceKey]map[int32]bool),
+		snmp:                make(map[deviceKey][]client.SNMPSensor),
+		tcp:                 make(map[deviceKey][]client.TCPSensor),
+		variables:           make(map[deviceKey][]client.Variable),
+		alertProfiles:       make(map[int32]client.AlertProfile),
+		deviceAlertProfiles: make(map[deviceKey]map[int32]bool),
+		agentAlertProfiles:  make(map[int32]map[int32]bool),
+		contacts:            make(map[int32]client.WebhookContact),
+		profileContacts:     make(map[int32]map[int32]bool),
+		customDrivers:       make(map[int32]client.CustomDriver),
+		driverAssociations:  make(map[int32]client.CustomDriverAssociation),
+		driverCredentials:   make(map[int32]*client.CustomDriverCredentials),
+		snmpAuth:            make(map[deviceKey]client.SNMPAuthentication),
+		requests:            make(map[string]int),
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+// Client returns an API client pointed at the server
+func (s *Server) Client() *client.Client {
+	return client.NewClient(s.URL, s.APIKey)
+}
+
+// Requests returns how many requests with method and path the server has
+// received, e.g. to check that an update skipped unchanged fields
+func (s *Server) Requests(method, path string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.requests[method+" "+path]
+}
+
+// newID returns a fresh object ID. Callers must hold s.mu.
+func (s *Server) newID() int32 {
+	s.nextID++
+	return s.nextID
+}
+
+// route is a parsed request path, e.g. /agent/1/device/2 has
+// segments ["agent", "1", "device", "2"]
+type route struct {
+	segments []string
+}
+
+// match reports whether the route has the given shape, where "{id}" matches
+// a numeric segment. Matched IDs are returned in order.
+func (r route) match(pattern ...string) ([]int32, bool) {
+	if len(r.segments) != len(pattern) {
+		return nil, false
+	}
+	var ids []int32
+	for i, p := range pattern {
+		if p == "{id}" {
+			id, err := strconv.ParseInt(r.segments[i], 10, 32)
+			if err != nil {
+				return nil, false
+			}
+			ids = append(ids, int32(id))
+			continue
+		}
+		if r.segments[i] != p {
+			return nil, false
+		}
+	}
+	return ids, true
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("X-Api-Key") != s.APIKey {
+		writeError(w, http.StatusUnauthorized, "invalid API key")
+		return
+	}
+
+	rt := route{segments: strings.Split(strings.Trim(r.URL.Path, "/"), "/")}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests[r.Method+" "+r.URL.Path]++
+
+	for _, h := range []func(http.ResponseWriter, *http.Request, route) bool{
+		s.handleAgents,
+		s.handleDevices,
+		s.handleDeviceTypes,
+		s.handleTags,
+		s.handleSensors,
+		s.handleVariables,
+		s.handleAlertProfiles,
+		s.handleContacts,
+		s.handleCustomDrivers,
+		s.handleSNMPAuthentication,
+	} {
+		if h(w, r, rt) {
+			return
+		}
+	}
+	writeError(w, http.StatusNotFound, fmt.Sprintf("no route for %s %s", r.Method, r.URL.Path))
+}
+
+// writeJSON writes v with a 200 status
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// writeError writes an ErrorResponse body with the given status
+func writeError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(client.ErrorResponse{Message: message})
+}
+
+func notFound(w http.ResponseWriter, what string, id int32) {
+	writeError(w, http.StatusNotFound, fmt.Sprintf("%s %d not found", what, id))
+}
+
+// decodeBody decodes the JSON request body into v, answering 400 on failure
+func decodeBody(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body: "+err.Error())
+		return false
+	}
+	return true
+}
+
+// paginate returns the requested page of items using the page_size and
+// page_number (1-based) query parameters. Without page_size every item is returned.
+func paginate[T any](r *http.Request, items []T) []T {
+	size, err := strconv.Atoi(r.URL.Query().Get("page_size"))
+	if err != nil || size <= 0 {
+		return items
+	}
+	page, err := strconv.Atoi(r.URL.Query().Get("page_number"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+	start := (page - 1) * size
+	if start >= len(items) {
+		return []T{}
+	}
+	end := start + size
+	if end > len(items) {
+		end = len(items)
+	}
+	return items[start:end]
+}
+
+// sortedKeys returns the keys of m in ascending order so that list
+// responses are stable across pages
+func sortedKeys[V any](m map[int32]V) []int32 {
+	keys := make([]int32, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+	return keys
+}
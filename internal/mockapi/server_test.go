@@ -0,0 +1,198 @@
+package mockapi
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/domotz/terraform-provider-domotz/internal/client"
+)
+
+func TestServer_Authentication(t *testing.T) {
+	server := New()
+	defer server.Close()
+
+	c := client.NewClient(server.URL, "wrong-key")
+	_, err := c.ListAgents(context.Background())
+	if !errors.Is(err, client.ErrUnauthorized) {
+		t.Errorf("Expected unauthorized error, got %v", err)
+	}
+}
+
+func TestServer_Devices(t *testing.T) {
+	server := New()
+	defer server.Close()
+	server.AddAgent(client.Agent{ID: 1, DisplayName: "HQ"})
+
+	c := server.Client()
+	ctx := context.Background()
+
+	device, err := c.CreateDevice(ctx, 1, client.CreateDeviceRequest{
+		DisplayName: "web",
+		IPAddresses: []string{"203.0.113.10"},
+	})
+	if err != nil {
+		t.Fatalf("CreateDevice: %v", err)
+	}
+	if device.ID == 0 || device.Importance != "FLOATING" {
+		t.Errorf("Unexpected created device: %+v", device)
+	}
+
+	importance, zone, vendor, deviceType := "VITAL", "Rack A", "AWS", int32(12)
+	updated, err := c.UpdateDevice(ctx, 1, device.ID, client.UpdateDeviceRequest{
+		Importance: &importance,
+		Zone:       &zone,
+		UserData:   &client.UpdateDeviceUserData{Vendor: &vendor, Type: &deviceType},
+	})
+	if err != nil {
+		t.Fatalf("UpdateDevice: %v", err)
+	}
+	// The zone is written through details/zone and read back in details
+	if updated.Importance != "VITAL" || updated.Details.Zone != "Rack A" || updated.UserData.Vendor != "AWS" || updated.UserData.Type != 12 {
+		t.Errorf("Unexpected updated device: %+v", updated)
+	}
+
+	// Empty values clear the field
+	cleared, noType := "", int32(0)
+	updated, err = c.UpdateDevice(ctx, 1, device.ID, client.UpdateDeviceRequest{
+		Zone:     &cleared,
+		UserData: &client.UpdateDeviceUserData{Vendor: &cleared, Type: &noType},
+	})
+	if err != nil {
+		t.Fatalf("UpdateDevice: %v", err)
+	}
+	if updated.Details.Zone != "" || updated.UserData != (client.DeviceUserData{}) {
+		t.Errorf("Expected zone and user_data to be cleared, got %+v", updated)
+	}
+
+	server.DeleteDevice(1, device.ID)
+	var notFound *client.NotFoundError
+	if _, err := c.GetDevice(ctx, 1, device.ID); !errors.As(err, &notFound) {
+		t.Errorf("Expected NotFoundError after out-of-band delete, got %v", err)
+	}
+}
+
+func TestServer_Tags(t *testing.T) {
+	server := New()
+	defer server.Close()
+	server.AddAgent(client.Agent{ID: 1})
+	device := server.AddDevice(client.Device{AgentID: 1, DisplayName: "switch"})
+
+	c := server.Client()
+	ctx := context.Background()
+
+	// Create answers 204 with no body; the client finds the tag by name
+	tag, err := c.CreateTag(ctx, client.CreateTagRequest{Name: "prod", Colour: "red"})
+	if err != nil {
+		t.Fatalf("CreateTag: %v", err)
+	}
+	if err := c.BindTagToDevice(ctx, 1, device.ID, tag.ID); err != nil {
+		t.Fatalf("BindTagToDevice: %v", err)
+	}
+	tags, err := c.ListDeviceTags(ctx, 1, device.ID)
+	if err != nil || len(tags) != 1 || tags[0].ID != tag.ID {
+		t.Fatalf("ListDeviceTags = %v, %v", tags, err)
+	}
+
+	server.DeleteTag(tag.ID)
+	if ids := server.DeviceTagIDs(1, device.ID); len(ids) != 0 {
+		t.Errorf("Expected bindings to be removed with the tag, got %v", ids)
+	}
+}
+
+func TestServer_TCPSensorConflict(t *testing.T) {
+	server := New()
+	defer server.Close()
+	server.AddAgent(client.Agent{ID: 1})
+	device := server.AddDevice(client.Device{AgentID: 1})
+
+	c := server.Client()
+	ctx := context.Background()
+
+	// Create answers 201 with no body; the client finds the sensor by port
+	sensor, err := c.CreateTCPSensor(ctx, 1, device.ID, client.CreateTCPSensorRequest{Port: 443})
+	if err != nil {
+		t.Fatalf("CreateTCPSensor: %v", err)
+	}
+	if sensor.Port != 443 {
+		t.Errorf("Unexpected sensor: %+v", sensor)
+	}
+
+	_, err = c.CreateTCPSensor(ctx, 1, device.ID, client.CreateTCPSensorRequest{Port: 443})
+	if !errors.Is(err, client.ErrConflict) {
+		t.Errorf("Expected conflict for duplicate port, got %v", err)
+	}
+}
+
+func TestServer_VariablesPagination(t *testing.T) {
+	server := New()
+	defer server.Close()
+	server.AddAgent(client.Agent{ID: 1})
+	device := server.AddDevice(client.Device{AgentID: 1})
+
+	variables := make([]client.Variable, 250)
+	for i := range variables {
+		variables[i] = client.Variable{Label: "metric"}
+	}
+	server.AddVariables(1, device.ID, variables...)
+
+	got, err := server.Client().ListVariables(context.Background(), 1, device.ID)
+	if err != nil {
+		t.Fatalf("ListVariables: %v", err)
+	}
+	if len(got) != len(variables) {
+		t.Errorf("Expected %d variables, got %d", len(variables), len(got))
+	}
+}
+
+func TestServer_AlertProfiles(t *testing.T) {
+	server := New()
+	defer server.Close()
+	server.AddAgent(client.Agent{ID: 1, DisplayName: "HQ"})
+	device := server.AddDevice(client.Device{AgentID: 1, DisplayName: "router"})
+
+	c := server.Client()
+	ctx := context.Background()
+
+	_, err := c.CreateAlertProfile(ctx, client.CreateAlertProfileRequest{Name: "bad", Events: []string{"device_on_fire"}})
+	if !errors.Is(err, client.ErrValidation) {
+		t.Errorf("Expected validation error for unknown event, got %v", err)
+	}
+	_, err = c.CreateAlertProfile(ctx, client.CreateAlertProfileRequest{
+		Name:       "bad",
+		Events:     []string{client.AlertEventDeviceDown},
+		Thresholds: []client.AlertProfileThreshold{{Variable: "CPU Usage", Operator: client.AlertThresholdGreaterThan, Value: "90"}},
+	})
+	if !errors.Is(err, client.ErrValidation) {
+		t.Errorf("Expected validation error for thresholds without the threshold event, got %v", err)
+	}
+
+	profile, err := c.CreateAlertProfile(ctx, client.CreateAlertProfileRequest{
+		Name:   "Core",
+		Events: []string{client.AlertEventDeviceDown},
+	})
+	if err != nil {
+		t.Fatalf("CreateAlertProfile: %v", err)
+	}
+	if err := c.BindAlertProfileToDevice(ctx, 1, device.ID, profile.ID); err != nil {
+		t.Fatalf("BindAlertProfileToDevice: %v", err)
+	}
+	if err := c.BindAlertProfileToAgent(ctx, 1, profile.ID); err != nil {
+		t.Fatalf("BindAlertProfileToAgent: %v", err)
+	}
+	profiles, err := c.ListDeviceAlertProfiles(ctx, 1, device.ID)
+	if err != nil || len(profiles) != 1 || profiles[0].Name != "Core" {
+		t.Fatalf("ListDeviceAlertProfiles = %+v, %v", profiles, err)
+	}
+
+	// Deleting the profile drops its bindings
+	if err := c.DeleteAlertProfile(ctx, profile.ID); err != nil {
+		t.Fatalf("DeleteAlertProfile: %v", err)
+	}
+	if ids := server.DeviceAlertProfileIDs(1, device.ID); len(ids) != 0 {
+		t.Errorf("Expected device bindings to be removed, got %v", ids)
+	}
+	if ids := server.AgentAlertProfileIDs(1); len(ids) != 0 {
+		t.Errorf("Expected agent bindings to be removed, got %v", ids)
+	}
+}
@@ -0,0 +1,124 @@
+package mockapi
+
+import (
+	"net/http"
+
+	"github.com/domotz/terraform-provider-domotz/internal/client"
+)
+
+// Device authentication statuses reported by the mock collector
+const (
+	AuthenticationStatusNone          = "NO_AUTHENTICATION"
+	AuthenticationStatusAuthenticated = "AUTHENTICATED"
+)
+
+// SNMPAuthentication returns the SNMP settings stored for a device, secrets
+// included, if any
+func (s *Server) SNMPAuthentication(agentID, deviceID int32) (client.SNMPAuthentication, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	auth, ok := s.snmpAuth[deviceKey{agentID, deviceID}]
+	return auth, ok
+}
+
+// SetSNMPAuthentication replaces the SNMP settings of a device out of band
+func (s *Server) SetSNMPAuthentication(agentID, deviceID int32, auth client.SNMPAuthentication) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.snmpAuth[deviceKey{agentID, deviceID}] = auth
+}
+
+// SetAuthenticationStatus changes the authentication status the collector
+// reports for a device, e.g. after the device's community was changed
+func (s *Server) SetAuthenticationStatus(agentID, deviceID int32, status string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key := deviceKey{agentID, deviceID}
+	if device, ok := s.devices[key]; ok {
+		device.AuthenticationStatus = status
+		s.devices[key] = device
+	}
+}
+
+// validSNMPAuthentication answers 400 unless the fields required by the
+// version are set
+func validSNMPAuthentication(w http.ResponseWriter, auth client.SNMPAuthentication) bool {
+	var missing string
+	switch auth.Version {
+	case client.SNMPVersionV1, client.SNMPVersionV2:
+		if auth.ReadCommunity == "" {
+			missing = "read_community"
+		}
+	case client.SNMPVersionV3NoAuth:
+		if auth.Username == "" {
+			missing = "username"
+		}
+	case client.SNMPVersionV3AuthNoPriv:
+		if auth.Username == "" || auth.AuthenticationProtocol == "" || auth.AuthenticationKey == "" {
+			missing = "username, authentication_protocol and authentication_key"
+		}
+	case client.SNMPVersionV3AuthPriv:
+		if auth.Username == "" || auth.AuthenticationProtocol == "" || auth.AuthenticationKey == "" ||
+			auth.EncryptionProtocol == "" || auth.EncryptionKey == "" {
+			missing = "username, authentication and encryption protocols and keys"
+		}
+	default:
+		writeError(w, http.StatusBadRequest, "unsupported version "+auth.Version)
+		return false
+	}
+	if missing != "" {
+		writeError(w, http.StatusBadRequest, auth.Version+" requires "+missing)
+		return false
+	}
+	return true
+}
+
+func (s *Server) handleSNMPAuthentication(w http.ResponseWriter, r *http.Request, rt route) bool {
+	ids, ok := rt.match("agent", "{id}", "device", "{id}", "snmp-authentication")
+	if !ok {
+		return false
+	}
+	key := deviceKey{ids[0], ids[1]}
+	device, exists := s.devices[key]
+	if !exists {
+		notFound(w, "device", ids[1])
+		return true
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		auth, configured := s.snmpAuth[key]
+		if !configured {
+			notFound(w, "SNMP authentication for device", ids[1])
+			return true
+		}
+		// Secrets are write-only
+		writeJSON(w, client.SNMPAuthentication{
+			Version:                auth.Version,
+			Username:               auth.Username,
+			AuthenticationProtocol: auth.AuthenticationProtocol,
+			EncryptionProtocol:     auth.EncryptionProtocol,
+		})
+	case http.MethodPut:
+		var auth client.SNMPAuthentication
+		if !decodeBody(w, r, &auth) || !validSNMPAuthentication(w, auth) {
+			return true
+		}
+		s.snmpAuth[key] = auth
+		device.AuthenticationStatus = AuthenticationStatusAuthenticated
+		s.devices[key] = device
+		w.WriteHeader(http.StatusNoContent)
+	case http.MethodDelete:
+		if _, configured := s.snmpAuth[key]; !configured {
+			notFound(w, "SNMP authentication for device", ids[1])
+			return true
+		}
+		delete(s.snmpAuth, key)
+		device.AuthenticationStatus = AuthenticationStatusNone
+		s.devices[key] = device
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		return false
+	}
+	return true
+}
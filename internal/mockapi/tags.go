@@ -0,0 +1,177 @@
+package mockapi
+
+import (
+	"net/http"
+
+	"github.com/domotz/terraform-provider-domotz/internal/client"
+)
+
+// AddTag seeds a custom tag. A zero ID is replaced by a fresh one.
+func (s *Server) AddTag(tag client.Tag) client.Tag {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if tag.ID == 0 {
+		tag.ID = s.newID()
+	}
+	s.tags[tag.ID] = tag
+	return tag
+}
+
+// Tag returns the stored custom tag, if any
+func (s *Server) Tag(tagID int32) (client.Tag, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	tag, ok := s.tags[tagID]
+	return tag, ok
+}
+
+// DeleteTag removes a custom tag and its bindings out of band
+func (s *Server) DeleteTag(tagID int32) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.deleteTag(tagID)
+}
+
+// BindTag binds a tag to a device out of band, e.g. from the Domotz UI
+func (s *Server) BindTag(agentID, deviceID, tagID int32) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.bind(deviceKey{agentID, deviceID}, tagID)
+}
+
+// UnbindTag removes a device tag binding out of band
+func (s *Server) UnbindTag(agentID, deviceID, tagID int32) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.bindings[deviceKey{agentID, deviceID}], tagID)
+}
+
+// DeviceTagIDs returns the IDs of the tags bound to a device
+func (s *Server) DeviceTagIDs(agentID, deviceID int32) []int32 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return sortedKeys(s.bindings[deviceKey{agentID, deviceID}])
+}
+
+// deleteTag removes a tag and its bindings. Callers must hold s.mu.
+func (s *Server) deleteTag(tagID int32) {
+	delete(s.tags, tagID)
+	for _, tags := range s.bindings {
+		delete(tags, tagID)
+	}
+}
+
+// bind records a binding. Callers must hold s.mu.
+func (s *Server) bind(key deviceKey, tagID int32) {
+	if s.bindings[key] == nil {
+		s.bindings[key] = make(map[int32]bool)
+	}
+	s.bindings[key][tagID] = true
+}
+
+func (s *Server) handleTags(w http.ResponseWriter, r *http.Request, rt route) bool {
+	if _, ok := rt.match("custom-tag"); ok {
+		switch r.Method {
+		case http.MethodGet:
+			tags := make([]client.Tag, 0, len(s.tags))
+			for _, id := range sortedKeys(s.tags) {
+				tags = append(tags, s.tags[id])
+			}
+			writeJSON(w, client.TagsResponse{Tags: paginate(r, tags)})
+		case http.MethodPost:
+			var req client.CreateTagRequest
+			if !decodeBody(w, r, &req) {
+				return true
+			}
+			if req.Name == "" {
+				writeError(w, http.StatusBadRequest, "name is required")
+				return true
+			}
+			for _, t := range s.tags {
+				if t.Name == req.Name {
+					writeError(w, http.StatusConflict, "a tag named "+req.Name+" already exists")
+					return true
+				}
+			}
+			id := s.newID()
+			s.tags[id] = client.Tag{ID: id, Name: req.Name, Colour: req.Colour}
+			// The real API answers 204 without the created tag
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			return false
+		}
+		return true
+	}
+
+	if ids, ok := rt.match("custom-tag", "{id}"); ok {
+		tag, exists := s.tags[ids[0]]
+		if !exists {
+			notFound(w, "tag", ids[0])
+			return true
+		}
+		switch r.Method {
+		case http.MethodPut:
+			var req client.UpdateTagRequest
+			if !decodeBody(w, r, &req) {
+				return true
+			}
+			if req.Name != nil {
+				tag.Name = *req.Name
+			}
+			if req.Colour != nil {
+				tag.Colour = *req.Colour
+			}
+			s.tags[tag.ID] = tag
+			w.WriteHeader(http.StatusNoContent)
+		case http.MethodDelete:
+			s.deleteTag(tag.ID)
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			return false
+		}
+		return true
+	}
+
+	if ids, ok := rt.match("agent", "{id}", "device", "{id}", "custom-tag", "binding"); ok && r.Method == http.MethodGet {
+		key := deviceKey{ids[0], ids[1]}
+		if _, exists := s.devices[key]; !exists {
+			notFound(w, "device", ids[1])
+			return true
+		}
+		tags := []client.Tag{}
+		for _, id := range sortedKeys(s.bindings[key]) {
+			tags = append(tags, s.tags[id])
+		}
+		writeJSON(w, paginate(r, tags))
+		return true
+	}
+
+	if ids, ok := rt.match("agent", "{id}", "device", "{id}", "custom-tag", "{id}", "binding"); ok {
+		key := deviceKey{ids[0], ids[1]}
+		if _, exists := s.devices[key]; !exists {
+			notFound(w, "device", ids[1])
+			return true
+		}
+		if _, exists := s.tags[ids[2]]; !exists {
+			notFound(w, "tag", ids[2])
+			return true
+		}
+		switch r.Method {
+		case http.MethodPost:
+			s.bind(key, ids[2])
+			w.WriteHeader(http.StatusNoContent)
+		case http.MethodDelete:
+			if !s.bindings[key][ids[2]] {
+				notFound(w, "binding for tag", ids[2])
+				return true
+			}
+			delete(s.bindings[key], ids[2])
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			return false
+		}
+		return true
+	}
+
+	return false
+}
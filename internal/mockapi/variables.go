@@ -0,0 +1,49 @@
+package mockapi
+
+import (
+	"net/http"
+
+	"github.com/domotz/terraform-provider-domotz/internal/client"
+)
+
+// AddVariables seeds variables (metrics) on a device. Zero IDs are replaced by fresh ones.
+func (s *Server) AddVariables(agentID, deviceID int32, variables ...client.Variable) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key := deviceKey{agentID, deviceID}
+	for _, v := range variables {
+		if v.ID == 0 {
+			v.ID = s.newID()
+		}
+		s.variables[key] = append(s.variables[key], v)
+	}
+}
+
+func (s *Server) handleVariables(w http.ResponseWriter, r *http.Request, rt route) bool {
+	if r.Method != http.MethodGet {
+		return false
+	}
+
+	if ids, ok := rt.match("agent", "{id}", "device", "{id}", "variable"); ok {
+		key := deviceKey{ids[0], ids[1]}
+		if _, exists := s.devices[key]; !exists {
+			notFound(w, "device", ids[1])
+			return true
+		}
+		writeJSON(w, paginate(r, append([]client.Variable{}, s.variables[key]...)))
+		return true
+	}
+
+	if ids, ok := rt.match("agent", "{id}", "device", "{id}", "variable", "{id}"); ok {
+		for _, v := range s.variables[deviceKey{ids[0], ids[1]}] {
+			if v.ID == ids[2] {
+				writeJSON(w, v)
+				return true
+			}
+		}
+		notFound(w, "variable", ids[2])
+		return true
+	}
+
+	return false
+}
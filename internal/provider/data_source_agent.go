@@ -3,14 +3,20 @@ package provider
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"github.com/domotz/terraform-provider-domotz/internal/client"
+	"github.com/hashicorp/terraform-plugin-framework-validators/datasourcevalidator"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 )
 
-var _ datasource.DataSource = &AgentDataSource{}
+var (
+	_ datasource.DataSource                     = &AgentDataSource{}
+	_ datasource.DataSourceWithConfigValidators = &AgentDataSource{}
+)
 
 func NewAgentDataSource() datasource.DataSource {
 	return &AgentDataSource{}
@@ -21,11 +27,14 @@ type AgentDataSource struct {
 }
 
 type AgentDataSourceModel struct {
-	ID          types.Int64  `tfsdk:"id"`
-	DisplayName types.String `tfsdk:"display_name"`
-	Status      types.String `tfsdk:"status"`
-	TeamID      types.Int64  `tfsdk:"team_id"`
-	TeamName    types.String `tfsdk:"team_name"`
+	ID               types.Int64  `tfsdk:"id"`
+	DisplayName      types.String `tfsdk:"display_name"`
+	Status           types.String `tfsdk:"status"`
+	StatusLastChange types.String `tfsdk:"status_last_change"`
+	TeamID           types.Int64  `tfsdk:"team_id"`
+	TeamName         types.String `tfsdk:"team_name"`
+	CreationTime     types.String `tfsdk:"creation_time"`
+	OnlineAt         types.String `tfsdk:"online_at"`
 }
 
 func (d *AgentDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
@@ -34,20 +43,26 @@ func (d *AgentDataSource) Metadata(_ context.Context, req datasource.MetadataReq
 
 func (d *AgentDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
 	resp.Schema = schema.Schema{
-		Description: "Retrieves details of a specific Domotz agent (collector).",
+		Description: "Retrieves details of a specific Domotz agent (collector), looked up by ID or by display name.",
 		Attributes: map[string]schema.Attribute{
 			"id": schema.Int64Attribute{
-				Description: "Collector ID",
-				Required:    true,
+				Description: "Collector ID. Exactly one of id or display_name must be set.",
+				Optional:    true,
+				Computed:    true,
 			},
 			"display_name": schema.StringAttribute{
-				Description: "Collector display name",
+				Description: "Collector display name. When used for lookup it must match exactly one collector.",
+				Optional:    true,
 				Computed:    true,
 			},
 			"status": schema.StringAttribute{
 				Description: "Collector status (ONLINE, OFFLINE)",
 				Computed:    true,
 			},
+			"status_last_change": schema.StringAttribute{
+				Description: "When the status last changed (RFC 3339)",
+				Computed:    true,
+			},
 			"team_id": schema.Int64Attribute{
 				Description: "Team/area ID",
 				Computed:    true,
@@ -56,10 +71,27 @@ func (d *AgentDataSource) Schema(_ context.Context, _ datasource.SchemaRequest,
 				Description: "Team/area name",
 				Computed:    true,
 			},
+			"creation_time": schema.StringAttribute{
+				Description: "When the collector was created (RFC 3339)",
+				Computed:    true,
+			},
+			"online_at": schema.StringAttribute{
+				Description: "When the collector last came online (RFC 3339)",
+				Computed:    true,
+			},
 		},
 	}
 }
 
+func (d *AgentDataSource) ConfigValidators(_ context.Context) []datasource.ConfigValidator {
+	return []datasource.ConfigValidator{
+		datasourcevalidator.ExactlyOneOf(
+			path.MatchRoot("id"),
+			path.MatchRoot("display_name"),
+		),
+	}
+}
+
 func (d *AgentDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
 	if req.ProviderData == nil {
 		return
@@ -84,16 +116,70 @@ func (d *AgentDataSource) Read(ctx context.Context, req datasource.ReadRequest,
 		return
 	}
 
-	agent, err := d.client.GetAgent(ctx, int32(config.ID.ValueInt64()))
-	if err != nil {
-		resp.Diagnostics.AddError("Error reading agent", err.Error())
-		return
+	var agent *client.Agent
+	if !config.ID.IsNull() {
+		var err error
+		agent, err = d.client.GetAgent(ctx, int32(config.ID.ValueInt64()))
+		if err != nil {
+			resp.Diagnostics.AddError("Error reading agent", apiErrorDetail(err))
+			return
+		}
+	} else {
+		agent = d.findByDisplayName(ctx, config.DisplayName.ValueString(), resp)
+		if agent == nil {
+			return
+		}
 	}
 
+	config.ID = types.Int64Value(int64(agent.ID))
 	config.DisplayName = types.StringValue(agent.DisplayName)
 	config.Status = types.StringValue(agent.Status.Value)
+	config.StatusLastChange = timeOrNull(agent.Status.LastChange)
 	config.TeamID = types.Int64Value(int64(agent.Team.ID))
 	config.TeamName = types.StringValue(agent.Team.Name)
+	config.CreationTime = timeOrNull(agent.CreationTime)
+	config.OnlineAt = timeOrNull(agent.OnlineAt)
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &config)...)
 }
+
+// findByDisplayName returns the only collector named displayName, or adds an
+// error and returns nil when there is none or more than one
+func (d *AgentDataSource) findByDisplayName(ctx context.Context, displayName string, resp *datasource.ReadResponse) *client.Agent {
+	agents, err := d.client.ListAgents(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError("Error listing agents", apiErrorDetail(err))
+		return nil
+	}
+
+	var matches []client.Agent
+	for _, agent := range agents {
+		if agent.DisplayName == displayName {
+			matches = append(matches, agent)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		resp.Diagnostics.AddAttributeError(
+			path.Root("display_name"),
+			"Agent not found",
+			fmt.Sprintf("No collector is named %q.", displayName),
+		)
+		return nil
+	case 1:
+		return &matches[0]
+	}
+
+	ids := make([]string, 0, len(matches))
+	for _, agent := range matches {
+		ids = append(ids, fmt.Sprint(agent.ID))
+	}
+	resp.Diagnostics.AddAttributeError(
+		path.Root("display_name"),
+		"Ambiguous agent display name",
+		fmt.Sprintf("%d collectors are named %q (IDs %s). Look the collector up by id instead.",
+			len(matches), displayName, strings.Join(ids, ", ")),
+	)
+	return nil
+}
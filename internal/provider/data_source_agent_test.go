@@ -0,0 +1,110 @@
+package provider
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/domotz/terraform-provider-domotz/internal/client"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccAgentDataSource(t *testing.T) {
+	testAccMockServer(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccAgentDataSourceConfig(testAccAgentID),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.domotz_agent.test", "display_name", "Test Collector"),
+					resource.TestCheckResourceAttr("data.domotz_agent.test", "status", "ONLINE"),
+					resource.TestCheckResourceAttr("data.domotz_agent.test", "team_id", "7"),
+					resource.TestCheckResourceAttr("data.domotz_agent.test", "team_name", "Operations"),
+					resource.TestCheckResourceAttr("data.domotz_agent.test", "status_last_change", "2024-01-02T03:04:05Z"),
+					resource.TestCheckResourceAttr("data.domotz_agent.test", "creation_time", "2023-06-01T00:00:00Z"),
+					resource.TestCheckResourceAttr("data.domotz_agent.test", "online_at", "2024-01-02T03:04:05Z"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccAgentDataSource_byDisplayName(t *testing.T) {
+	server := testAccMockServer(t)
+	server.AddAgent(client.Agent{ID: 300201, DisplayName: "Acme - Leeds", Team: client.Team{ID: 8, Name: "Field Sites"}})
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccAgentDataSourceByNameConfig("Acme - Leeds"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.domotz_agent.test", "id", "300201"),
+					resource.TestCheckResourceAttr("data.domotz_agent.test", "team_name", "Field Sites"),
+					resource.TestCheckNoResourceAttr("data.domotz_agent.test", "online_at"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccAgentDataSource_displayNameErrors(t *testing.T) {
+	server := testAccMockServer(t)
+	server.AddAgent(client.Agent{ID: 300201, DisplayName: "Acme - Leeds"})
+	server.AddAgent(client.Agent{ID: 300202, DisplayName: "Acme - Leeds"})
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccAgentDataSourceByNameConfig("Acme - York"),
+				ExpectError: regexp.MustCompile(`No collector is named "Acme - York"`),
+			},
+			{
+				Config:      testAccAgentDataSourceByNameConfig("Acme - Leeds"),
+				ExpectError: regexp.MustCompile(`2 collectors are named "Acme - Leeds" \(IDs 300201, 300202\)`),
+			},
+			{
+				Config: fmt.Sprintf(`
+data "domotz_agent" "test" {
+  id           = %d
+  display_name = "Test Collector"
+}
+`, testAccAgentID),
+				ExpectError: regexp.MustCompile(`Invalid Attribute Combination`),
+			},
+		},
+	})
+}
+
+func TestAccAgentDataSource_notFound(t *testing.T) {
+	testAccMockServer(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccAgentDataSourceConfig(1),
+				ExpectError: regexp.MustCompile(`Error reading agent`),
+			},
+		},
+	})
+}
+
+func testAccAgentDataSourceByNameConfig(displayName string) string {
+	return fmt.Sprintf(`
+data "domotz_agent" "test" {
+  display_name = %q
+}
+`, displayName)
+}
+
+func testAccAgentDataSourceConfig(agentID int32) string {
+	return fmt.Sprintf(`
+data "domotz_agent" "test" {
+  id = %d
+}
+`, agentID)
+}
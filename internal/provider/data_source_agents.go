@@ -0,0 +1,183 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+
+	"github.com/domotz/terraform-provider-domotz/internal/client"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var _ datasource.DataSource = &AgentsDataSource{}
+
+func NewAgentsDataSource() datasource.DataSource {
+	return &AgentsDataSource{}
+}
+
+type AgentsDataSource struct {
+	client *client.Client
+}
+
+type AgentsDataSourceModel struct {
+	Status    types.String     `tfsdk:"status"`
+	TeamID    types.Int64      `tfsdk:"team_id"`
+	TeamName  types.String     `tfsdk:"team_name"`
+	NameRegex types.String     `tfsdk:"name_regex"`
+	Agents    []AgentListModel `tfsdk:"agents"`
+}
+
+type AgentListModel struct {
+	ID           types.Int64  `tfsdk:"id"`
+	DisplayName  types.String `tfsdk:"display_name"`
+	Status       types.String `tfsdk:"status"`
+	TeamID       types.Int64  `tfsdk:"team_id"`
+	TeamName     types.String `tfsdk:"team_name"`
+	CreationTime types.String `tfsdk:"creation_time"`
+	OnlineAt     types.String `tfsdk:"online_at"`
+}
+
+func (d *AgentsDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_agents"
+}
+
+func (d *AgentsDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Retrieves the Domotz collectors (agents) visible to the API key, optionally filtered. " +
+			"All filters that are set must match.",
+		Attributes: map[string]schema.Attribute{
+			"status": schema.StringAttribute{
+				Description: "Only return collectors with this status (ONLINE, OFFLINE)",
+				Optional:    true,
+				Validators: []validator.String{
+					stringvalidator.OneOf("ONLINE", "OFFLINE"),
+				},
+			},
+			"team_id": schema.Int64Attribute{
+				Description: "Only return collectors assigned to this team ID",
+				Optional:    true,
+			},
+			"team_name": schema.StringAttribute{
+				Description: "Only return collectors assigned to the team with this name",
+				Optional:    true,
+				Validators: []validator.String{
+					stringvalidator.ConflictsWith(path.MatchRoot("team_id")),
+				},
+			},
+			"name_regex": schema.StringAttribute{
+				Description: "Only return collectors whose display name matches this regular expression (RE2 syntax)",
+				Optional:    true,
+			},
+			"agents": schema.ListNestedAttribute{
+				Description: "Matching collectors, ordered by ID",
+				Computed:    true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"id": schema.Int64Attribute{
+							Description: "Collector ID",
+							Computed:    true,
+						},
+						"display_name": schema.StringAttribute{
+							Description: "Collector display name",
+							Computed:    true,
+						},
+						"status": schema.StringAttribute{
+							Description: "Collector status (ONLINE, OFFLINE)",
+							Computed:    true,
+						},
+						"team_id": schema.Int64Attribute{
+							Description: "Team/area ID",
+							Computed:    true,
+						},
+						"team_name": schema.StringAttribute{
+							Description: "Team/area name",
+							Computed:    true,
+						},
+						"creation_time": schema.StringAttribute{
+							Description: "When the collector was created (RFC 3339)",
+							Computed:    true,
+						},
+						"online_at": schema.StringAttribute{
+							Description: "When the collector last came online (RFC 3339)",
+							Computed:    true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (d *AgentsDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	c, ok := req.ProviderData.(*client.Client)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *client.Client, got: %T", req.ProviderData),
+		)
+		return
+	}
+
+	d.client = c
+}
+
+func (d *AgentsDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var config AgentsDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var nameRegex *regexp.Regexp
+	if !config.NameRegex.IsNull() {
+		var err error
+		nameRegex, err = regexp.Compile(config.NameRegex.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("name_regex"), "Invalid name_regex", err.Error())
+			return
+		}
+	}
+
+	agents, err := d.client.ListAgents(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError("Error listing agents", apiErrorDetail(err))
+		return
+	}
+	sort.Slice(agents, func(i, j int) bool { return agents[i].ID < agents[j].ID })
+
+	config.Agents = make([]AgentListModel, 0, len(agents))
+	for _, agent := range agents {
+		switch {
+		case !config.Status.IsNull() && agent.Status.Value != config.Status.ValueString():
+			continue
+		case !config.TeamID.IsNull() && int64(agent.Team.ID) != config.TeamID.ValueInt64():
+			continue
+		case !config.TeamName.IsNull() && agent.Team.Name != config.TeamName.ValueString():
+			continue
+		case nameRegex != nil && !nameRegex.MatchString(agent.DisplayName):
+			continue
+		}
+
+		config.Agents = append(config.Agents, AgentListModel{
+			ID:           types.Int64Value(int64(agent.ID)),
+			DisplayName:  types.StringValue(agent.DisplayName),
+			Status:       types.StringValue(agent.Status.Value),
+			TeamID:       types.Int64Value(int64(agent.Team.ID)),
+			TeamName:     types.StringValue(agent.Team.Name),
+			CreationTime: timeOrNull(agent.CreationTime),
+			OnlineAt:     timeOrNull(agent.OnlineAt),
+		})
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &config)...)
+}
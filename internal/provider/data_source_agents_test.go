@@ -0,0 +1,77 @@
+package provider
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/domotz/terraform-provider-domotz/internal/client"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccAgentsDataSource(t *testing.T) {
+	server := testAccMockServer(t)
+	field := client.Team{ID: 8, Name: "Field Sites"}
+	server.AddAgent(client.Agent{ID: 300101, DisplayName: "Acme - Leeds", Status: client.AgentStatus{Value: "ONLINE"}, Team: field,
+		CreationTime: time.Date(2025, 3, 4, 9, 30, 0, 0, time.UTC)})
+	server.AddAgent(client.Agent{ID: 300102, DisplayName: "Acme - York", Status: client.AgentStatus{Value: "OFFLINE"}, Team: field})
+	server.AddAgent(client.Agent{ID: 300103, DisplayName: "Globex - Leeds", Status: client.AgentStatus{Value: "ONLINE"}, Team: field})
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+data "domotz_agents" "all" {}
+
+data "domotz_agents" "acme_online" {
+  status     = "ONLINE"
+  team_name  = "Field Sites"
+  name_regex = "^Acme - "
+}
+
+data "domotz_agents" "operations" {
+  team_id = 7
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.domotz_agents.all", "agents.#", "4"),
+					resource.TestCheckResourceAttr("data.domotz_agents.all", "agents.0.id", strconv.Itoa(testAccAgentID)),
+					resource.TestCheckResourceAttr("data.domotz_agents.all", "agents.0.creation_time", "2023-06-01T00:00:00Z"),
+					resource.TestCheckResourceAttr("data.domotz_agents.all", "agents.0.online_at", "2024-01-02T03:04:05Z"),
+					// Unset timestamps are null, not the zero time
+					resource.TestCheckNoResourceAttr("data.domotz_agents.all", "agents.2.online_at"),
+
+					resource.TestCheckResourceAttr("data.domotz_agents.acme_online", "agents.#", "1"),
+					resource.TestCheckResourceAttr("data.domotz_agents.acme_online", "agents.0.id", "300101"),
+					resource.TestCheckResourceAttr("data.domotz_agents.acme_online", "agents.0.display_name", "Acme - Leeds"),
+					resource.TestCheckResourceAttr("data.domotz_agents.acme_online", "agents.0.team_id", "8"),
+					resource.TestCheckResourceAttr("data.domotz_agents.acme_online", "agents.0.creation_time", "2025-03-04T09:30:00Z"),
+
+					resource.TestCheckResourceAttr("data.domotz_agents.operations", "agents.#", "1"),
+					resource.TestCheckResourceAttr("data.domotz_agents.operations", "agents.0.team_name", "Operations"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccAgentsDataSource_invalidRegex(t *testing.T) {
+	testAccMockServer(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+data "domotz_agents" "test" {
+  name_regex = %q
+}
+`, "Acme ("),
+				ExpectError: regexp.MustCompile(`Invalid name_regex`),
+			},
+		},
+	})
+}
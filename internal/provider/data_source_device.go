@@ -2,15 +2,24 @@ package provider
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"github.com/domotz/terraform-provider-domotz/internal/client"
+	"github.com/hashicorp/terraform-plugin-framework-validators/datasourcevalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 )
 
-var _ datasource.DataSource = &DeviceDataSource{}
+var (
+	_ datasource.DataSource                     = &DeviceDataSource{}
+	_ datasource.DataSourceWithConfigValidators = &DeviceDataSource{}
+)
 
 func NewDeviceDataSource() datasource.DataSource {
 	return &DeviceDataSource{}
@@ -21,13 +30,28 @@ type DeviceDataSource struct {
 }
 
 type DeviceDataSourceModel struct {
-	AgentID     types.Int64    `tfsdk:"agent_id"`
-	ID          types.Int64    `tfsdk:"id"`
-	DisplayName types.String   `tfsdk:"display_name"`
-	Protocol    types.String   `tfsdk:"protocol"`
-	IPAddresses types.List     `tfsdk:"ip_addresses"`
-	Importance  types.String   `tfsdk:"importance"`
-	UserData    *UserDataModel `tfsdk:"user_data"`
+	AgentID              types.Int64         `tfsdk:"agent_id"`
+	ID                   types.Int64         `tfsdk:"id"`
+	IPAddress            types.String        `tfsdk:"ip_address"`
+	HWAddress            types.String        `tfsdk:"hw_address"`
+	DisplayName          types.String        `tfsdk:"display_name"`
+	Protocol             types.String        `tfsdk:"protocol"`
+	IPAddresses          types.List          `tfsdk:"ip_addresses"`
+	Importance           types.String        `tfsdk:"importance"`
+	Vendor               types.String        `tfsdk:"vendor"`
+	Model                types.String        `tfsdk:"model"`
+	UserData             *UserDataModel      `tfsdk:"user_data"`
+	Zone                 types.String        `tfsdk:"zone"`
+	Status               types.String        `tfsdk:"status"`
+	AuthenticationStatus types.String        `tfsdk:"authentication_status"`
+	SNMPStatus           types.String        `tfsdk:"snmp_status"`
+	AgentReachable       types.Bool          `tfsdk:"agent_reachable"`
+	IsJammed             types.Bool          `tfsdk:"is_jammed"`
+	GroupingType         types.String        `tfsdk:"grouping_type"`
+	OS                   *DeviceOSModel      `tfsdk:"os"`
+	Details              *DeviceDetailsModel `tfsdk:"details"`
+	FirstSeenAt          types.String        `tfsdk:"first_seen_at"`
+	LastStatusChange     types.String        `tfsdk:"last_status_change"`
 }
 
 func (d *DeviceDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
@@ -36,18 +60,37 @@ func (d *DeviceDataSource) Metadata(_ context.Context, req datasource.MetadataRe
 
 func (d *DeviceDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
 	resp.Schema = schema.Schema{
-		Description: "Retrieves details of a specific device.",
-		Attributes: map[string]schema.Attribute{
+		Description: "Retrieves details of a specific device, looked up by ID, IP address, MAC address or display name. " +
+			"Lookups other than by ID must match exactly one device.",
+		Attributes: withDeviceDetailAttributes(map[string]schema.Attribute{
 			"agent_id": schema.Int64Attribute{
-				Description: "ID of the collector managing the device",
-				Required:    true,
+				Description: "ID of the collector managing the device. Required with id; otherwise, when omitted, every collector is searched.",
+				Optional:    true,
+				Computed:    true,
 			},
 			"id": schema.Int64Attribute{
-				Description: "Device ID",
-				Required:    true,
+				Description: "Device ID. Exactly one of id, ip_address, hw_address or display_name must be set.",
+				Optional:    true,
+				Computed:    true,
+				Validators: []validator.Int64{
+					int64validator.AlsoRequires(path.MatchRoot("agent_id")),
+				},
+			},
+			"ip_address": schema.StringAttribute{
+				Description: "IP address of the device to look up",
+				Optional:    true,
+			},
+			"hw_address": schema.StringAttribute{
+				Description: "MAC address of the device (e.g., 00:11:22:33:44:55). Can be used for lookup.",
+				Optional:    true,
+				Computed:    true,
+				Validators: []validator.String{
+					stringvalidator.RegexMatches(hwAddressRegexp, "must be a MAC address such as 00:11:22:33:44:55"),
+				},
 			},
 			"display_name": schema.StringAttribute{
-				Description: "Device display name",
+				Description: "Device display name. Can be used for lookup.",
+				Optional:    true,
 				Computed:    true,
 			},
 			"protocol": schema.StringAttribute{
@@ -63,6 +106,14 @@ func (d *DeviceDataSource) Schema(_ context.Context, _ datasource.SchemaRequest,
 				Description: "Device importance level",
 				Computed:    true,
 			},
+			"vendor": schema.StringAttribute{
+				Description: "Auto-discovered device vendor",
+				Computed:    true,
+			},
+			"model": schema.StringAttribute{
+				Description: "Auto-discovered device model",
+				Computed:    true,
+			},
 			"user_data": schema.SingleNestedAttribute{
 				Description: "Custom metadata for the device",
 				Computed:    true,
@@ -80,12 +131,24 @@ func (d *DeviceDataSource) Schema(_ context.Context, _ datasource.SchemaRequest,
 						Computed:    true,
 					},
 					"type": schema.StringAttribute{
-						Description: "Device type",
+						Description: "Device type label",
 						Computed:    true,
+						CustomType:  DeviceTypeType{},
 					},
 				},
 			},
-		},
+		}),
+	}
+}
+
+func (d *DeviceDataSource) ConfigValidators(_ context.Context) []datasource.ConfigValidator {
+	return []datasource.ConfigValidator{
+		datasourcevalidator.ExactlyOneOf(
+			path.MatchRoot("id"),
+			path.MatchRoot("ip_address"),
+			path.MatchRoot("hw_address"),
+			path.MatchRoot("display_name"),
+		),
 	}
 }
 
@@ -113,19 +176,49 @@ func (d *DeviceDataSource) Read(ctx context.Context, req datasource.ReadRequest,
 		return
 	}
 
-	device, err := d.client.GetDevice(
-		ctx,
-		int32(config.AgentID.ValueInt64()),
-		int32(config.ID.ValueInt64()),
-	)
-	if err != nil {
-		resp.Diagnostics.AddError("Error reading device", err.Error())
-		return
+	var device *client.Device
+	if !config.ID.IsNull() {
+		var err error
+		device, err = d.client.GetDevice(
+			ctx,
+			int32(config.AgentID.ValueInt64()),
+			int32(config.ID.ValueInt64()),
+		)
+		if err != nil {
+			resp.Diagnostics.AddError("Error reading device", apiErrorDetail(err))
+			return
+		}
+	} else {
+		device = d.findDevice(ctx, config, resp)
+		if device == nil {
+			return
+		}
 	}
 
+	if config.AgentID.IsNull() {
+		config.AgentID = types.Int64Value(int64(device.AgentID))
+	}
+	config.ID = types.Int64Value(int64(device.ID))
+	// Keep the configured spelling of the MAC address used for lookup
+	if config.HWAddress.IsNull() {
+		config.HWAddress = stringOrNull(device.HWAddress)
+	}
 	config.DisplayName = types.StringValue(device.DisplayName)
 	config.Protocol = types.StringValue(device.Protocol)
 	config.Importance = types.StringValue(device.Importance)
+	config.Vendor = types.StringValue(device.Vendor)
+	config.Model = types.StringValue(device.Model)
+	config.Zone = stringOrNull(device.Details.Zone)
+	config.Status = stringOrNull(device.Status)
+	config.AuthenticationStatus = stringOrNull(device.AuthenticationStatus)
+	config.SNMPStatus = stringOrNull(device.SNMPStatus)
+	config.AgentReachable = types.BoolValue(device.AgentReachable)
+	config.IsJammed = types.BoolValue(device.IsJammed)
+	config.GroupingType = stringOrNull(device.GroupingType)
+	config.OS = deviceOSModel(device.OS)
+	config.Details = deviceDetailsModel(device.Details)
+	config.FirstSeenAt = timeOrNull(device.FirstSeenAt)
+	config.LastStatusChange = timeOrNull(device.LastStatusChange)
 
 	ipAddressesList, diags := types.ListValueFrom(ctx, types.StringType, device.IPAddresses)
 	resp.Diagnostics.Append(diags...)
@@ -134,14 +227,53 @@ func (d *DeviceDataSource) Read(ctx context.Context, req datasource.ReadRequest,
 	}
 	config.IPAddresses = ipAddressesList
 
-	if device.UserData.Name != "" || device.UserData.Model != "" || device.UserData.Vendor != "" || device.UserData.Type != 0 {
-		config.UserData = &UserDataModel{
-			Name:   types.StringValue(device.UserData.Name),
-			Model:  types.StringValue(device.UserData.Model),
-			Vendor: types.StringValue(device.UserData.Vendor),
-			Type:   types.StringValue(fmt.Sprintf("%d", device.UserData.Type)),
+	config.UserData = userDataFromAPI(device.UserData, deviceTypeLabel(ctx, d.client, device.UserData.Type), false)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &config)...)
+}
+
+// findDevice returns the only device matching the configured ip_address,
+// hw_address or display_name, searching every collector unless agent_id is
+// set. It adds an error and returns nil when there is no match or more than one.
+func (d *DeviceDataSource) findDevice(ctx context.Context, config DeviceDataSourceModel, resp *datasource.ReadResponse) *client.Device {
+	selector := deviceSelector{
+		hwAddress:   config.HWAddress.ValueString(),
+		ipAddress:   config.IPAddress.ValueString(),
+		displayName: config.DisplayName.ValueString(),
+	}
+
+	var agentIDs []int32
+	if !config.AgentID.IsNull() {
+		agentIDs = []int32{int32(config.AgentID.ValueInt64())}
+	} else {
+		agents, err := d.client.ListAgents(ctx)
+		if err != nil {
+			resp.Diagnostics.AddError("Error listing agents", apiErrorDetail(err))
+			return nil
+		}
+		for _, agent := range agents {
+			agentIDs = append(agentIDs, agent.ID)
 		}
 	}
 
-	resp.Diagnostics.Append(resp.State.Set(ctx, &config)...)
+	matches, err := selector.find(ctx, d.client, agentIDs)
+	if err != nil {
+		resp.Diagnostics.AddError("Error listing devices", apiErrorDetail(err))
+		return nil
+	}
+
+	scope, hint := "on any collector", "Set agent_id, or look the device up by agent_id and id."
+	if !config.AgentID.IsNull() {
+		scope, hint = fmt.Sprintf("on agent %d", agentIDs[0]), "Look the device up by id instead."
+	}
+
+	device, err := selector.only(matches, scope)
+	var notFound *client.NotFoundError
+	switch {
+	case errors.As(err, &notFound):
+		resp.Diagnostics.AddError("Device not found", fmt.Sprintf("No device with %s %s.", selector, scope))
+	case err != nil:
+		resp.Diagnostics.AddError("Ambiguous device lookup", err.Error()+"\n"+hint)
+	}
+	return device
 }
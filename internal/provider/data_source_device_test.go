@@ -0,0 +1,212 @@
+package provider
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/domotz/terraform-provider-domotz/internal/client"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccDeviceDataSource(t *testing.T) {
+	server := testAccMockServer(t)
+	device := server.AddDevice(client.Device{
+		AgentID:     testAccAgentID,
+		DisplayName: "core-switch",
+		Protocol:    "IP",
+		IPAddresses: []string{"192.168.1.2"},
+		Importance:  "VITAL",
+		UserData:    client.DeviceUserData{Name: "sw-01", Vendor: "Ubiquiti", Type: 7},
+	})
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+data "domotz_device" "test" {
+  agent_id = %d
+  id       = %d
+}
+`, testAccAgentID, device.ID),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.domotz_device.test", "display_name", "core-switch"),
+					resource.TestCheckResourceAttr("data.domotz_device.test", "protocol", "IP"),
+					resource.TestCheckResourceAttr("data.domotz_device.test", "ip_addresses.#", "1"),
+					resource.TestCheckResourceAttr("data.domotz_device.test", "ip_addresses.0", "192.168.1.2"),
+					resource.TestCheckResourceAttr("data.domotz_device.test", "importance", "VITAL"),
+					resource.TestCheckResourceAttr("data.domotz_device.test", "user_data.name", "sw-01"),
+					resource.TestCheckResourceAttr("data.domotz_device.test", "user_data.vendor", "Ubiquiti"),
+					resource.TestCheckResourceAttr("data.domotz_device.test", "user_data.type", "Router"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccDeviceDataSource_details(t *testing.T) {
+	server := testAccMockServer(t)
+	switchDevice := server.AddDevice(client.Device{
+		AgentID:              testAccAgentID,
+		DisplayName:          "core-switch",
+		Protocol:             "IP",
+		IPAddresses:          []string{"192.168.1.2"},
+		HWAddress:            "00:1A:2B:3C:4D:5E",
+		Vendor:               "Ubiquiti Inc",
+		Model:                "USW-Pro-24",
+		Status:               "ONLINE",
+		AuthenticationStatus: "AUTHENTICATED",
+		SNMPStatus:           "OK",
+		AgentReachable:       true,
+		GroupingType:         "MAIN",
+		OS:                   client.DeviceOS{Name: "EdgeOS", Version: "6.5.59", Build: "14554"},
+		Details:              client.DeviceDetails{Serial: "F09FC2A1B2C3", FirmwareVersion: "6.5.59", Room: "Server room", Zone: "Rack A"},
+		FirstSeenAt:          time.Date(2023, 6, 1, 12, 0, 0, 0, time.FixedZone("CEST", 2*60*60)),
+		LastStatusChange:     time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+	})
+	bare := server.AddDevice(client.Device{
+		AgentID:     testAccAgentID,
+		DisplayName: "placeholder",
+		Protocol:    "DUMMY",
+	})
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+data "domotz_device" "switch" {
+  agent_id = %[1]d
+  id       = %[2]d
+}
+
+data "domotz_device" "bare" {
+  agent_id = %[1]d
+  id       = %[3]d
+}
+`, testAccAgentID, switchDevice.ID, bare.ID),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.domotz_device.switch", "hw_address", "00:1A:2B:3C:4D:5E"),
+					resource.TestCheckResourceAttr("data.domotz_device.switch", "vendor", "Ubiquiti Inc"),
+					resource.TestCheckResourceAttr("data.domotz_device.switch", "model", "USW-Pro-24"),
+					resource.TestCheckResourceAttr("data.domotz_device.switch", "zone", "Rack A"),
+					resource.TestCheckResourceAttr("data.domotz_device.switch", "status", "ONLINE"),
+					resource.TestCheckResourceAttr("data.domotz_device.switch", "authentication_status", "AUTHENTICATED"),
+					resource.TestCheckResourceAttr("data.domotz_device.switch", "snmp_status", "OK"),
+					resource.TestCheckResourceAttr("data.domotz_device.switch", "agent_reachable", "true"),
+					resource.TestCheckResourceAttr("data.domotz_device.switch", "is_jammed", "false"),
+					resource.TestCheckResourceAttr("data.domotz_device.switch", "grouping_type", "MAIN"),
+					resource.TestCheckResourceAttr("data.domotz_device.switch", "os.name", "EdgeOS"),
+					resource.TestCheckResourceAttr("data.domotz_device.switch", "os.version", "6.5.59"),
+					resource.TestCheckResourceAttr("data.domotz_device.switch", "os.build", "14554"),
+					resource.TestCheckResourceAttr("data.domotz_device.switch", "details.serial", "F09FC2A1B2C3"),
+					resource.TestCheckResourceAttr("data.domotz_device.switch", "details.firmware_version", "6.5.59"),
+					resource.TestCheckResourceAttr("data.domotz_device.switch", "details.room", "Server room"),
+					resource.TestCheckResourceAttr("data.domotz_device.switch", "first_seen_at", "2023-06-01T10:00:00Z"),
+					resource.TestCheckResourceAttr("data.domotz_device.switch", "last_status_change", "2024-01-02T03:04:05Z"),
+					resource.TestCheckNoResourceAttr("data.domotz_device.bare", "zone"),
+					resource.TestCheckNoResourceAttr("data.domotz_device.bare", "os.name"),
+					resource.TestCheckNoResourceAttr("data.domotz_device.bare", "details.serial"),
+					resource.TestCheckNoResourceAttr("data.domotz_device.bare", "first_seen_at"),
+					resource.TestCheckNoResourceAttr("data.domotz_device.bare", "last_status_change"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccDeviceDataSource_lookup(t *testing.T) {
+	server := testAccMockServer(t)
+	server.AddAgent(client.Agent{ID: testAccBranchAgentID, DisplayName: "Branch Collector"})
+	core := server.AddDevice(client.Device{
+		AgentID:     testAccAgentID,
+		DisplayName: "core-switch",
+		IPAddresses: []string{"10.0.0.1"},
+		HWAddress:   "00:1A:2B:3C:4D:5E",
+	})
+	server.AddDevice(client.Device{
+		AgentID:     testAccBranchAgentID,
+		DisplayName: "branch-router",
+		IPAddresses: []string{"10.0.0.254", "192.168.50.1"},
+	})
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+data "domotz_device" "by_ip" {
+  ip_address = "10.0.0.1"
+}
+
+data "domotz_device" "by_mac" {
+  hw_address = "00-1a-2b-3c-4d-5e"
+}
+
+data "domotz_device" "by_name" {
+  display_name = "branch-router"
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.domotz_device.by_ip", "id", fmt.Sprint(core.ID)),
+					resource.TestCheckResourceAttr("data.domotz_device.by_ip", "agent_id", fmt.Sprint(testAccAgentID)),
+					resource.TestCheckResourceAttr("data.domotz_device.by_ip", "hw_address", "00:1A:2B:3C:4D:5E"),
+					resource.TestCheckResourceAttr("data.domotz_device.by_mac", "id", fmt.Sprint(core.ID)),
+					resource.TestCheckResourceAttr("data.domotz_device.by_mac", "display_name", "core-switch"),
+					// Searched across collectors
+					resource.TestCheckResourceAttr("data.domotz_device.by_name", "agent_id", fmt.Sprint(testAccBranchAgentID)),
+					resource.TestCheckResourceAttr("data.domotz_device.by_name", "ip_addresses.#", "2"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccDeviceDataSource_lookupErrors(t *testing.T) {
+	server := testAccMockServer(t)
+	server.AddAgent(client.Agent{ID: testAccBranchAgentID, DisplayName: "Branch Collector"})
+	server.AddDevice(client.Device{AgentID: testAccAgentID, DisplayName: "gateway", IPAddresses: []string{"192.168.1.1"}})
+	server.AddDevice(client.Device{AgentID: testAccBranchAgentID, DisplayName: "gateway", IPAddresses: []string{"192.168.1.1"}})
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+data "domotz_device" "test" {
+  id = 1
+}
+`,
+				ExpectError: regexp.MustCompile(`Invalid Attribute Combination`),
+			},
+			{
+				Config: `
+data "domotz_device" "test" {
+  ip_address = "192.168.1.1"
+}
+`,
+				ExpectError: regexp.MustCompile(`(?s)2 devices with IP address 192.168.1.1 on any collector.*"gateway" on agent 200891.*"gateway" on agent 200892`),
+			},
+			{
+				// Narrowing to one collector resolves the ambiguity
+				Config: fmt.Sprintf(`
+data "domotz_device" "test" {
+  agent_id     = %d
+  display_name = "gateway"
+}
+`, testAccBranchAgentID),
+				Check: resource.TestCheckResourceAttr("data.domotz_device.test", "agent_id", fmt.Sprint(testAccBranchAgentID)),
+			},
+			{
+				Config: `
+data "domotz_device" "test" {
+  display_name = "printer"
+}
+`,
+				ExpectError: regexp.MustCompile(`No device with display name "printer" on any collector`),
+			},
+		},
+	})
+}
@@ -0,0 +1,124 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/domotz/terraform-provider-domotz/internal/client"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var _ datasource.DataSource = &DeviceTypesDataSource{}
+
+func NewDeviceTypesDataSource() datasource.DataSource {
+	return &DeviceTypesDataSource{}
+}
+
+type DeviceTypesDataSource struct {
+	client *client.Client
+}
+
+type DeviceTypesDataSourceModel struct {
+	DeviceTypes []DeviceTypeModel `tfsdk:"device_types"`
+}
+
+type DeviceTypeModel struct {
+	ID           types.Int64  `tfsdk:"id"`
+	Identifier   types.String `tfsdk:"identifier"`
+	Label        types.String `tfsdk:"label"`
+	Vital        types.Bool   `tfsdk:"vital"`
+	Capabilities types.List   `tfsdk:"capabilities"`
+}
+
+func (d *DeviceTypesDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_device_types"
+}
+
+func (d *DeviceTypesDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Retrieves the catalogue of device types accepted by domotz_device user_data.type.",
+		Attributes: map[string]schema.Attribute{
+			"device_types": schema.ListNestedAttribute{
+				Description: "List of device types",
+				Computed:    true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"id": schema.Int64Attribute{
+							Description: "Device type ID",
+							Computed:    true,
+						},
+						"identifier": schema.StringAttribute{
+							Description: "Stable identifier (e.g., ROUTER)",
+							Computed:    true,
+						},
+						"label": schema.StringAttribute{
+							Description: "Display label (e.g., Router)",
+							Computed:    true,
+						},
+						"vital": schema.BoolAttribute{
+							Description: "Whether devices of this type are VITAL by default",
+							Computed:    true,
+						},
+						"capabilities": schema.ListAttribute{
+							Description: "Monitoring capabilities of this type (e.g., snmp)",
+							Computed:    true,
+							ElementType: types.StringType,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (d *DeviceTypesDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	c, ok := req.ProviderData.(*client.Client)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *client.Client, got: %T", req.ProviderData),
+		)
+		return
+	}
+
+	d.client = c
+}
+
+func (d *DeviceTypesDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var config DeviceTypesDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	deviceTypes, err := d.client.ListDeviceTypes(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError("Error listing device types", apiErrorDetail(err))
+		return
+	}
+
+	config.DeviceTypes = make([]DeviceTypeModel, 0, len(deviceTypes))
+	for _, t := range deviceTypes {
+		capabilities, diags := types.ListValueFrom(ctx, types.StringType, append([]string{}, t.Capabilities...))
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		config.DeviceTypes = append(config.DeviceTypes, DeviceTypeModel{
+			ID:           types.Int64Value(int64(t.ID)),
+			Identifier:   types.StringValue(t.Identifier),
+			Label:        types.StringValue(t.Label),
+			Vital:        types.BoolValue(t.Vital),
+			Capabilities: capabilities,
+		})
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &config)...)
+}
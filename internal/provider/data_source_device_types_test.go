@@ -0,0 +1,30 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccDeviceTypesDataSource(t *testing.T) {
+	testAccMockServer(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `data "domotz_device_types" "test" {}`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckTypeSetElemNestedAttrs("data.domotz_device_types.test", "device_types.*", map[string]string{
+						"id":             "7",
+						"identifier":     "ROUTER",
+						"label":          "Router",
+						"vital":          "true",
+						"capabilities.#": "2",
+						"capabilities.0": "snmp",
+					}),
+				),
+			},
+		},
+	})
+}
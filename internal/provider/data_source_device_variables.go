@@ -122,7 +122,7 @@ func (d *DeviceVariablesDataSource) Read(ctx context.Context, req datasource.Rea
 		int32(config.DeviceID.ValueInt64()),
 	)
 	if err != nil {
-		resp.Diagnostics.AddError("Error listing device variables", err.Error())
+		resp.Diagnostics.AddError("Error listing device variables", apiErrorDetail(err))
 		return
 	}
 
@@ -0,0 +1,38 @@
+package provider
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/domotz/terraform-provider-domotz/internal/client"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccDeviceVariablesDataSource(t *testing.T) {
+	server := testAccMockServer(t)
+	device := server.AddDevice(client.Device{AgentID: testAccAgentID, DisplayName: "ups"})
+	server.AddVariables(testAccAgentID, device.ID,
+		client.Variable{ID: 1, Label: "Battery Charge", Path: "battery.charge", Value: "98", Unit: "%", Metric: "battery"},
+		client.Variable{ID: 2, Label: "Load", Path: "output.load", Value: "31", PreviousValue: "29", Unit: "%", Metric: "load"},
+	)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+data "domotz_device_variables" "test" {
+  agent_id  = %d
+  device_id = %d
+}
+`, testAccAgentID, device.ID),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.domotz_device_variables.test", "variables.#", "2"),
+					resource.TestCheckResourceAttr("data.domotz_device_variables.test", "variables.0.label", "Battery Charge"),
+					resource.TestCheckResourceAttr("data.domotz_device_variables.test", "variables.0.value", "98"),
+					resource.TestCheckResourceAttr("data.domotz_device_variables.test", "variables.1.previous_value", "29"),
+				),
+			},
+		},
+	})
+}
@@ -2,12 +2,19 @@ package provider
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net"
+	"regexp"
+	"strings"
 
 	"github.com/domotz/terraform-provider-domotz/internal/client"
-	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 )
 
@@ -22,26 +29,59 @@ type DevicesDataSource struct {
 }
 
 type DevicesDataSourceModel struct {
-	AgentID types.Int64       `tfsdk:"agent_id"`
-	Devices []DeviceListModel `tfsdk:"devices"`
+	AgentID types.Int64         `tfsdk:"agent_id"`
+	Filter  *DevicesFilterModel `tfsdk:"filter"`
+	Devices []DeviceListModel   `tfsdk:"devices"`
+}
+
+type DevicesFilterModel struct {
+	Importance   types.String `tfsdk:"importance"`
+	Protocol     types.String `tfsdk:"protocol"`
+	Status       types.String `tfsdk:"status"`
+	Vendor       types.String `tfsdk:"vendor"`
+	VendorRegex  types.String `tfsdk:"vendor_regex"`
+	Model        types.String `tfsdk:"model"`
+	ModelRegex   types.String `tfsdk:"model_regex"`
+	UserDataType types.String `tfsdk:"user_data_type"`
+	Zone         types.String `tfsdk:"zone"`
+	IPCIDR       types.String `tfsdk:"ip_cidr"`
+	MACPrefix    types.String `tfsdk:"mac_prefix"`
+	TagIDs       types.Set    `tfsdk:"tag_ids"`
 }
 
 type DeviceListModel struct {
-	ID          types.Int64  `tfsdk:"id"`
-	DisplayName types.String `tfsdk:"display_name"`
-	Protocol    types.String `tfsdk:"protocol"`
-	IPAddresses types.List   `tfsdk:"ip_addresses"`
-	Importance  types.String `tfsdk:"importance"`
-	Vendor      types.String `tfsdk:"vendor"`    // Auto-discovered vendor
-	Model       types.String `tfsdk:"model"`     // Auto-discovered model
-	UserData    types.Object `tfsdk:"user_data"` // User-editable metadata
+	ID                   types.Int64         `tfsdk:"id"`
+	DisplayName          types.String        `tfsdk:"display_name"`
+	Protocol             types.String        `tfsdk:"protocol"`
+	IPAddresses          types.List          `tfsdk:"ip_addresses"`
+	HWAddress            types.String        `tfsdk:"hw_address"`
+	Importance           types.String        `tfsdk:"importance"`
+	Vendor               types.String        `tfsdk:"vendor"`    // Auto-discovered vendor
+	Model                types.String        `tfsdk:"model"`     // Auto-discovered model
+	UserData             *UserDataModel      `tfsdk:"user_data"` // User-editable metadata
+	Zone                 types.String        `tfsdk:"zone"`
+	Status               types.String        `tfsdk:"status"`
+	AuthenticationStatus types.String        `tfsdk:"authentication_status"`
+	SNMPStatus           types.String        `tfsdk:"snmp_status"`
+	AgentReachable       types.Bool          `tfsdk:"agent_reachable"`
+	IsJammed             types.Bool          `tfsdk:"is_jammed"`
+	GroupingType         types.String        `tfsdk:"grouping_type"`
+	OS                   *DeviceOSModel      `tfsdk:"os"`
+	Details              *DeviceDetailsModel `tfsdk:"details"`
+	FirstSeenAt          types.String        `tfsdk:"first_seen_at"`
+	LastStatusChange     types.String        `tfsdk:"last_status_change"`
+}
+
+type DeviceOSModel struct {
+	Name    types.String `tfsdk:"name"`
+	Version types.String `tfsdk:"version"`
+	Build   types.String `tfsdk:"build"`
 }
 
-type DeviceUserDataModel struct {
-	Name   types.String `tfsdk:"name"`
-	Model  types.String `tfsdk:"model"`
-	Vendor types.String `tfsdk:"vendor"`
-	Type   types.Int64  `tfsdk:"type"`
+type DeviceDetailsModel struct {
+	Serial          types.String `tfsdk:"serial"`
+	FirmwareVersion types.String `tfsdk:"firmware_version"`
+	Room            types.String `tfsdk:"room"`
 }
 
 func (d *DevicesDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
@@ -50,7 +90,76 @@ func (d *DevicesDataSource) Metadata(_ context.Context, req datasource.MetadataR
 
 func (d *DevicesDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
 	resp.Schema = schema.Schema{
-		Description: "Retrieves a list of devices for a specific collector.",
+		Description: "Retrieves a list of devices for a specific collector, optionally narrowed by a filter block.",
+		Blocks: map[string]schema.Block{
+			"filter": schema.SingleNestedBlock{
+				Description: "Only return devices matching every criterion that is set. importance, protocol and status " +
+					"are applied by the API; the others are applied by the provider.",
+				Attributes: map[string]schema.Attribute{
+					"importance": schema.StringAttribute{
+						Description: "Device importance level (VITAL, FLOATING)",
+						Optional:    true,
+						Validators: []validator.String{
+							stringvalidator.OneOf("VITAL", "FLOATING"),
+						},
+					},
+					"protocol": schema.StringAttribute{
+						Description: "Device protocol (e.g., IP, DUMMY)",
+						Optional:    true,
+					},
+					"status": schema.StringAttribute{
+						Description: "Device status (ONLINE, OFFLINE, DOWN)",
+						Optional:    true,
+						Validators: []validator.String{
+							stringvalidator.OneOf("ONLINE", "OFFLINE", "DOWN"),
+						},
+					},
+					"vendor": schema.StringAttribute{
+						Description: "Exact auto-discovered vendor",
+						Optional:    true,
+						Validators: []validator.String{
+							stringvalidator.ConflictsWith(path.MatchRelative().AtParent().AtName("vendor_regex")),
+						},
+					},
+					"vendor_regex": schema.StringAttribute{
+						Description: "Regular expression (RE2 syntax) the auto-discovered vendor must match",
+						Optional:    true,
+					},
+					"model": schema.StringAttribute{
+						Description: "Exact auto-discovered model",
+						Optional:    true,
+						Validators: []validator.String{
+							stringvalidator.ConflictsWith(path.MatchRelative().AtParent().AtName("model_regex")),
+						},
+					},
+					"model_regex": schema.StringAttribute{
+						Description: "Regular expression (RE2 syntax) the auto-discovered model must match",
+						Optional:    true,
+					},
+					"user_data_type": schema.StringAttribute{
+						Description: "Device type set in user_data, as a label (e.g., \"Router\") or numeric ID from the domotz_device_types data source",
+						Optional:    true,
+					},
+					"zone": schema.StringAttribute{
+						Description: "Zone the device belongs to",
+						Optional:    true,
+					},
+					"ip_cidr": schema.StringAttribute{
+						Description: "CIDR block (e.g., 10.0.0.0/24) containing at least one of the device's IP addresses",
+						Optional:    true,
+					},
+					"mac_prefix": schema.StringAttribute{
+						Description: "Prefix of the device's MAC address, e.g. an OUI such as 00:1A:2B. Case and separators are ignored.",
+						Optional:    true,
+					},
+					"tag_ids": schema.SetAttribute{
+						Description: "Tag IDs that must all be bound to the device",
+						Optional:    true,
+						ElementType: types.Int64Type,
+					},
+				},
+			},
+		},
 		Attributes: map[string]schema.Attribute{
 			"agent_id": schema.Int64Attribute{
 				Description: "ID of the collector managing the devices",
@@ -60,7 +169,7 @@ func (d *DevicesDataSource) Schema(_ context.Context, _ datasource.SchemaRequest
 				Description: "List of devices",
 				Computed:    true,
 				NestedObject: schema.NestedAttributeObject{
-					Attributes: map[string]schema.Attribute{
+					Attributes: withDeviceDetailAttributes(map[string]schema.Attribute{
 						"id": schema.Int64Attribute{
 							Description: "Device ID",
 							Computed:    true,
@@ -106,16 +215,131 @@ func (d *DevicesDataSource) Schema(_ context.Context, _ datasource.SchemaRequest
 									Description: "Device vendor",
 									Computed:    true,
 								},
-								"type": schema.Int64Attribute{
-									Description: "Device type ID",
+								"type": schema.StringAttribute{
+									Description: "Device type label",
 									Computed:    true,
+									CustomType:  DeviceTypeType{},
 								},
 							},
 						},
-					},
+						"hw_address": schema.StringAttribute{
+							Description: "MAC address",
+							Computed:    true,
+						},
+					}),
+				},
+			},
+		},
+	}
+}
+
+// withDeviceDetailAttributes adds the computed device fields shared by the
+// domotz_device and domotz_devices data sources to attributes
+func withDeviceDetailAttributes(attributes map[string]schema.Attribute) map[string]schema.Attribute {
+	for name, attribute := range map[string]schema.Attribute{
+		"zone": schema.StringAttribute{
+			Description: "Zone the device belongs to",
+			Computed:    true,
+		},
+		"status": schema.StringAttribute{
+			Description: "Device status (ONLINE, OFFLINE, DOWN)",
+			Computed:    true,
+		},
+		"authentication_status": schema.StringAttribute{
+			Description: "Authentication status the collector reports for the device (e.g., AUTHENTICATED, NO_AUTHENTICATION)",
+			Computed:    true,
+		},
+		"snmp_status": schema.StringAttribute{
+			Description: "SNMP status of the device",
+			Computed:    true,
+		},
+		"agent_reachable": schema.BoolAttribute{
+			Description: "Whether the collector can reach the device",
+			Computed:    true,
+		},
+		"is_jammed": schema.BoolAttribute{
+			Description: "Whether the device is jammed (excluded from monitoring after repeated failures)",
+			Computed:    true,
+		},
+		"grouping_type": schema.StringAttribute{
+			Description: "How the device is grouped with related devices",
+			Computed:    true,
+		},
+		"os": schema.SingleNestedAttribute{
+			Description: "Detected operating system, or null when unknown",
+			Computed:    true,
+			Attributes: map[string]schema.Attribute{
+				"name": schema.StringAttribute{
+					Description: "Operating system name",
+					Computed:    true,
+				},
+				"version": schema.StringAttribute{
+					Description: "Operating system version",
+					Computed:    true,
+				},
+				"build": schema.StringAttribute{
+					Description: "Operating system build",
+					Computed:    true,
 				},
 			},
 		},
+		"details": schema.SingleNestedAttribute{
+			Description: "Inventory details, or null when none are known",
+			Computed:    true,
+			Attributes: map[string]schema.Attribute{
+				"serial": schema.StringAttribute{
+					Description: "Serial number",
+					Computed:    true,
+				},
+				"firmware_version": schema.StringAttribute{
+					Description: "Firmware version",
+					Computed:    true,
+				},
+				"room": schema.StringAttribute{
+					Description: "Room the device is located in",
+					Computed:    true,
+				},
+			},
+		},
+		"first_seen_at": schema.StringAttribute{
+			Description: "When the device was first discovered (RFC 3339)",
+			Computed:    true,
+		},
+		"last_status_change": schema.StringAttribute{
+			Description: "When the device status last changed (RFC 3339)",
+			Computed:    true,
+		},
+	} {
+		attributes[name] = attribute
+	}
+	return attributes
+}
+
+// deviceOSModel converts the detected operating system, or returns nil when
+// none was detected
+func deviceOSModel(os client.DeviceOS) *DeviceOSModel {
+	if os == (client.DeviceOS{}) {
+		return nil
+	}
+	return &DeviceOSModel{
+		Name:    stringOrNull(os.Name),
+		Version: stringOrNull(os.Version),
+		Build:   stringOrNull(os.Build),
+	}
+}
+
+// deviceDetailsModel converts the inventory details, or returns nil when
+// none are known
+func deviceDetailsModel(details client.DeviceDetails) *DeviceDetailsModel {
+	// The zone is exposed as the top-level zone attribute
+	details.Zone = ""
+	if details == (client.DeviceDetails{}) {
+		return nil
+	}
+	return &DeviceDetailsModel{
+		Serial:          stringOrNull(details.Serial),
+		FirmwareVersion: stringOrNull(details.FirmwareVersion),
+		Room:            stringOrNull(details.Room),
 	}
 }
 
@@ -143,9 +367,21 @@ func (d *DevicesDataSource) Read(ctx context.Context, req datasource.ReadRequest
 		return
 	}
 
-	devices, err := d.client.ListDevices(ctx, int32(config.AgentID.ValueInt64()))
+	agentID := int32(config.AgentID.ValueInt64())
+	filter := newDeviceFilter(ctx, d.client, config.Filter, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	devices, err := d.client.ListDevicesFiltered(ctx, agentID, filter.server)
+	if err != nil {
+		resp.Diagnostics.AddError("Error listing devices", apiErrorDetail(err))
+		return
+	}
+
+	devices, err = filter.apply(ctx, d.client, agentID, devices)
 	if err != nil {
-		resp.Diagnostics.AddError("Error listing devices", err.Error())
+		resp.Diagnostics.AddError("Error filtering devices by tag", apiErrorDetail(err))
 		return
 	}
 
@@ -157,38 +393,181 @@ func (d *DevicesDataSource) Read(ctx context.Context, req datasource.ReadRequest
 			return
 		}
 
-		// Build user_data object
-		userDataAttrs := map[string]attr.Type{
-			"name":   types.StringType,
-			"model":  types.StringType,
-			"vendor": types.StringType,
-			"type":   types.Int64Type,
+		config.Devices = append(config.Devices, DeviceListModel{
+			ID:                   types.Int64Value(int64(device.ID)),
+			DisplayName:          types.StringValue(device.DisplayName),
+			Protocol:             types.StringValue(device.Protocol),
+			IPAddresses:          ipAddressesList,
+			HWAddress:            stringOrNull(device.HWAddress),
+			Importance:           types.StringValue(device.Importance),
+			Vendor:               types.StringValue(device.Vendor),
+			Model:                types.StringValue(device.Model),
+			UserData:             userDataFromAPI(device.UserData, deviceTypeLabel(ctx, d.client, device.UserData.Type), false),
+			Zone:                 stringOrNull(device.Details.Zone),
+			Status:               stringOrNull(device.Status),
+			AuthenticationStatus: stringOrNull(device.AuthenticationStatus),
+			SNMPStatus:           stringOrNull(device.SNMPStatus),
+			AgentReachable:       types.BoolValue(device.AgentReachable),
+			IsJammed:             types.BoolValue(device.IsJammed),
+			GroupingType:         stringOrNull(device.GroupingType),
+			OS:                   deviceOSModel(device.OS),
+			Details:              deviceDetailsModel(device.Details),
+			FirstSeenAt:          timeOrNull(device.FirstSeenAt),
+			LastStatusChange:     timeOrNull(device.LastStatusChange),
+		})
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &config)...)
+}
+
+// deviceFilter is a parsed filter block. server holds the criteria sent as
+// query parameters; apply checks every criterion, so the result is correct
+// even if the API ignores a parameter.
+type deviceFilter struct {
+	server       client.DeviceListFilter
+	vendor       string
+	vendorRegex  *regexp.Regexp
+	model        string
+	modelRegex   *regexp.Regexp
+	userDataType *int32
+	zone         string
+	ipNet        *net.IPNet
+	macPrefix    string
+	tagIDs       []int32
+}
+
+// newDeviceFilter parses m, adding an attribute error for each invalid
+// regular expression, device type, CIDR block or tag set
+func newDeviceFilter(ctx context.Context, c *client.Client, m *DevicesFilterModel, diags *diag.Diagnostics) deviceFilter {
+	var f deviceFilter
+	if m == nil {
+		return f
+	}
+
+	f.server = client.DeviceListFilter{
+		Importance: m.Importance.ValueString(),
+		Protocol:   m.Protocol.ValueString(),
+		Status:     m.Status.ValueString(),
+	}
+	f.vendor = m.Vendor.ValueString()
+	f.model = m.Model.ValueString()
+	f.zone = m.Zone.ValueString()
+	f.macPrefix = normalizeHWAddress(m.MACPrefix.ValueString())
+
+	compile := func(name string, v types.String) *regexp.Regexp {
+		if v.IsNull() {
+			return nil
+		}
+		re, err := regexp.Compile(v.ValueString())
+		if err != nil {
+			diags.AddAttributeError(path.Root("filter").AtName(name), "Invalid regular expression", err.Error())
 		}
+		return re
+	}
+	f.vendorRegex = compile("vendor_regex", m.VendorRegex)
+	f.modelRegex = compile("model_regex", m.ModelRegex)
 
-		userDataValues := map[string]attr.Value{
-			"name":   types.StringValue(device.UserData.Name),
-			"model":  types.StringValue(device.UserData.Model),
-			"vendor": types.StringValue(device.UserData.Vendor),
-			"type":   types.Int64Value(int64(device.UserData.Type)),
+	if !m.UserDataType.IsNull() {
+		typePath := path.Root("filter").AtName("user_data_type")
+		deviceType, err := c.ResolveDeviceType(ctx, m.UserDataType.ValueString())
+		var notFound *client.NotFoundError
+		switch {
+		case errors.As(err, &notFound):
+			diags.AddAttributeError(typePath, "Invalid device type",
+				fmt.Sprintf("%q is not a known device type. Use a label or ID listed by the domotz_device_types data source.", m.UserDataType.ValueString()))
+		case err != nil:
+			diags.AddAttributeError(typePath, "Error resolving device type", apiErrorDetail(err))
+		default:
+			f.userDataType = &deviceType.ID
 		}
+	}
 
-		userDataObj, diags := types.ObjectValue(userDataAttrs, userDataValues)
-		resp.Diagnostics.Append(diags...)
-		if resp.Diagnostics.HasError() {
-			return
+	if !m.IPCIDR.IsNull() {
+		_, ipNet, err := net.ParseCIDR(m.IPCIDR.ValueString())
+		if err != nil {
+			diags.AddAttributeError(path.Root("filter").AtName("ip_cidr"), "Invalid CIDR block", err.Error())
 		}
+		f.ipNet = ipNet
+	}
 
-		config.Devices = append(config.Devices, DeviceListModel{
-			ID:          types.Int64Value(int64(device.ID)),
-			DisplayName: types.StringValue(device.DisplayName),
-			Protocol:    types.StringValue(device.Protocol),
-			IPAddresses: ipAddressesList,
-			Importance:  types.StringValue(device.Importance),
-			Vendor:      types.StringValue(device.Vendor),
-			Model:       types.StringValue(device.Model),
-			UserData:    userDataObj,
-		})
+	if !m.TagIDs.IsNull() {
+		var tagIDs []int64
+		diags.Append(m.TagIDs.ElementsAs(ctx, &tagIDs, false)...)
+		for _, id := range tagIDs {
+			f.tagIDs = append(f.tagIDs, int32(id))
+		}
 	}
+	return f
+}
 
-	resp.Diagnostics.Append(resp.State.Set(ctx, &config)...)
+// apply returns the devices matching every criterion, in the order given.
+// Tag bindings are checked last, since they cost one request per device
+// however many tags are listed.
+func (f deviceFilter) apply(ctx context.Context, c *client.Client, agentID int32, devices []client.Device) ([]client.Device, error) {
+	var matched []client.Device
+	for _, device := range devices {
+		if f.matches(device) {
+			matched = append(matched, device)
+		}
+	}
+
+	if len(f.tagIDs) == 0 || len(matched) == 0 {
+		return matched, nil
+	}
+	refs := make([]client.DeviceRef, 0, len(matched))
+	for _, device := range matched {
+		refs = append(refs, client.DeviceRef{AgentID: agentID, DeviceID: device.ID})
+	}
+	tagged, err := c.DevicesWithTags(ctx, f.tagIDs, refs)
+	if err != nil {
+		return nil, err
+	}
+	keep := make(map[int32]bool, len(tagged))
+	for _, ref := range tagged {
+		keep[ref.DeviceID] = true
+	}
+	var next []client.Device
+	for _, device := range matched {
+		if keep[device.ID] {
+			next = append(next, device)
+		}
+	}
+	return next, nil
+}
+
+func (f deviceFilter) matches(device client.Device) bool {
+	switch {
+	case f.server.Importance != "" && device.Importance != f.server.Importance:
+		return false
+	case f.server.Protocol != "" && device.Protocol != f.server.Protocol:
+		return false
+	case f.server.Status != "" && device.Status != f.server.Status:
+		return false
+	case f.vendor != "" && device.Vendor != f.vendor:
+		return false
+	case f.vendorRegex != nil && !f.vendorRegex.MatchString(device.Vendor):
+		return false
+	case f.model != "" && device.Model != f.model:
+		return false
+	case f.modelRegex != nil && !f.modelRegex.MatchString(device.Model):
+		return false
+	case f.userDataType != nil && device.UserData.Type != *f.userDataType:
+		return false
+	case f.zone != "" && device.Details.Zone != f.zone:
+		return false
+	case f.macPrefix != "" && !strings.HasPrefix(normalizeHWAddress(device.HWAddress), f.macPrefix):
+		return false
+	case f.ipNet != nil && !containsAnyIP(f.ipNet, device.IPAddresses):
+		return false
+	}
+	return true
+}
+
+func containsAnyIP(ipNet *net.IPNet, addresses []string) bool {
+	for _, address := range addresses {
+		if ip := net.ParseIP(address); ip != nil && ipNet.Contains(ip) {
+			return true
+		}
+	}
+	return false
 }
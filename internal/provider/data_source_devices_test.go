@@ -0,0 +1,215 @@
+package provider
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/domotz/terraform-provider-domotz/internal/client"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccDevicesDataSource(t *testing.T) {
+	server := testAccMockServer(t)
+	// More devices than fit in one page, so the listing has to paginate
+	for i := 1; i <= 150; i++ {
+		server.AddDevice(client.Device{
+			AgentID:     testAccAgentID,
+			DisplayName: fmt.Sprintf("device-%03d", i),
+			Protocol:    "IP",
+			IPAddresses: []string{fmt.Sprintf("10.0.%d.%d", i/256, i%256)},
+			Vendor:      "Acme",
+		})
+	}
+	server.AddDevice(client.Device{
+		AgentID:          testAccAgentID,
+		DisplayName:      "device-151",
+		Protocol:         "IP",
+		Status:           "DOWN",
+		IsJammed:         true,
+		OS:               client.DeviceOS{Name: "RouterOS", Version: "7.12"},
+		Details:          client.DeviceDetails{Room: "Lobby"},
+		UserData:         client.DeviceUserData{Name: "edge-router", Type: 7},
+		LastStatusChange: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+	})
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+data "domotz_devices" "test" {
+  agent_id = %d
+}
+`, testAccAgentID),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.domotz_devices.test", "devices.#", "151"),
+					resource.TestCheckResourceAttr("data.domotz_devices.test", "devices.0.display_name", "device-001"),
+					resource.TestCheckResourceAttr("data.domotz_devices.test", "devices.0.vendor", "Acme"),
+					resource.TestCheckNoResourceAttr("data.domotz_devices.test", "devices.0.os.name"),
+					resource.TestCheckNoResourceAttr("data.domotz_devices.test", "devices.0.last_status_change"),
+					resource.TestCheckNoResourceAttr("data.domotz_devices.test", "devices.0.user_data.name"),
+					resource.TestCheckResourceAttr("data.domotz_devices.test", "devices.149.display_name", "device-150"),
+					resource.TestCheckResourceAttr("data.domotz_devices.test", "devices.150.status", "DOWN"),
+					resource.TestCheckResourceAttr("data.domotz_devices.test", "devices.150.is_jammed", "true"),
+					resource.TestCheckResourceAttr("data.domotz_devices.test", "devices.150.os.name", "RouterOS"),
+					resource.TestCheckResourceAttr("data.domotz_devices.test", "devices.150.os.version", "7.12"),
+					resource.TestCheckNoResourceAttr("data.domotz_devices.test", "devices.150.os.build"),
+					resource.TestCheckResourceAttr("data.domotz_devices.test", "devices.150.details.room", "Lobby"),
+					resource.TestCheckResourceAttr("data.domotz_devices.test", "devices.150.user_data.name", "edge-router"),
+					resource.TestCheckResourceAttr("data.domotz_devices.test", "devices.150.user_data.type", "Router"),
+					resource.TestCheckNoResourceAttr("data.domotz_devices.test", "devices.150.user_data.vendor"),
+					resource.TestCheckResourceAttr("data.domotz_devices.test", "devices.150.last_status_change", "2024-01-02T03:04:05Z"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccDevicesDataSource_filter(t *testing.T) {
+	server := testAccMockServer(t)
+	core := server.AddDevice(client.Device{
+		AgentID: testAccAgentID, DisplayName: "core-switch", Protocol: "IP", Status: "ONLINE", Importance: "VITAL",
+		IPAddresses: []string{"10.0.0.1"}, HWAddress: "00:1A:2B:00:00:01", Vendor: "Ubiquiti Inc", Model: "USW-Pro-24",
+		Details: client.DeviceDetails{Zone: "Rack A"}, UserData: client.DeviceUserData{Type: 12},
+	})
+	edge := server.AddDevice(client.Device{
+		AgentID: testAccAgentID, DisplayName: "edge-switch", Protocol: "IP", Status: "DOWN", Importance: "VITAL",
+		IPAddresses: []string{"10.0.1.1"}, HWAddress: "00-1a-2b-00-00-02", Vendor: "Ubiquiti Inc", Model: "USW-Lite-8",
+		Details: client.DeviceDetails{Zone: "Rack B"}, UserData: client.DeviceUserData{Type: 12},
+	})
+	server.AddDevice(client.Device{
+		AgentID: testAccAgentID, DisplayName: "laptop", Protocol: "IP", Status: "ONLINE", Importance: "FLOATING",
+		IPAddresses: []string{"192.168.1.20"}, HWAddress: "F0:18:98:00:00:03", Vendor: "Apple", Model: "MacBook",
+	})
+	server.AddDevice(client.Device{
+		AgentID: testAccAgentID, DisplayName: "placeholder", Protocol: "DUMMY", Status: "ONLINE", Importance: "FLOATING",
+	})
+	network := server.AddTag(client.Tag{Name: "network", Colour: "blue"})
+	critical := server.AddTag(client.Tag{Name: "critical", Colour: "red"})
+	server.BindTag(testAccAgentID, core.ID, network.ID)
+	server.BindTag(testAccAgentID, core.ID, critical.ID)
+	server.BindTag(testAccAgentID, edge.ID, network.ID)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+data "domotz_devices" "vital_online" {
+  agent_id = %[1]d
+  filter {
+    importance = "VITAL"
+    status     = "ONLINE"
+  }
+}
+
+data "domotz_devices" "ubiquiti_switches" {
+  agent_id = %[1]d
+  filter {
+    vendor      = "Ubiquiti Inc"
+    model_regex = "^USW-"
+  }
+}
+
+data "domotz_devices" "lan" {
+  agent_id = %[1]d
+  filter {
+    ip_cidr    = "10.0.0.0/16"
+    mac_prefix = "00:1a:2b"
+    zone       = "Rack B"
+  }
+}
+
+data "domotz_devices" "typed" {
+  agent_id = %[1]d
+  filter {
+    user_data_type = "server"
+    vendor_regex   = "(?i)ubiquiti"
+  }
+}
+
+data "domotz_devices" "typed_by_id" {
+  agent_id = %[1]d
+  filter {
+    user_data_type = "12"
+  }
+}
+
+data "domotz_devices" "dummy" {
+  agent_id = %[1]d
+  filter {
+    protocol = "DUMMY"
+  }
+}
+
+data "domotz_devices" "tagged" {
+  agent_id = %[1]d
+  filter {
+    tag_ids = [%[2]d, %[3]d]
+  }
+}
+`, testAccAgentID, network.ID, critical.ID),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.domotz_devices.vital_online", "devices.#", "1"),
+					resource.TestCheckResourceAttr("data.domotz_devices.vital_online", "devices.0.display_name", "core-switch"),
+					resource.TestCheckResourceAttr("data.domotz_devices.ubiquiti_switches", "devices.#", "2"),
+					resource.TestCheckResourceAttr("data.domotz_devices.lan", "devices.#", "1"),
+					resource.TestCheckResourceAttr("data.domotz_devices.lan", "devices.0.display_name", "edge-switch"),
+					resource.TestCheckResourceAttr("data.domotz_devices.typed", "devices.#", "2"),
+					resource.TestCheckResourceAttr("data.domotz_devices.typed_by_id", "devices.#", "2"),
+					resource.TestCheckResourceAttr("data.domotz_devices.dummy", "devices.#", "1"),
+					resource.TestCheckResourceAttr("data.domotz_devices.dummy", "devices.0.display_name", "placeholder"),
+					// Every listed tag must be bound
+					resource.TestCheckResourceAttr("data.domotz_devices.tagged", "devices.#", "1"),
+					resource.TestCheckResourceAttr("data.domotz_devices.tagged", "devices.0.display_name", "core-switch"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccDevicesDataSource_invalidFilter(t *testing.T) {
+	testAccMockServer(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+data "domotz_devices" "test" {
+  agent_id = %d
+  filter {
+    ip_cidr = "10.0.0.1"
+  }
+}
+`, testAccAgentID),
+				ExpectError: regexp.MustCompile(`Invalid CIDR block`),
+			},
+			{
+				Config: fmt.Sprintf(`
+data "domotz_devices" "test" {
+  agent_id = %d
+  filter {
+    vendor       = "Apple"
+    vendor_regex = "^Apple"
+  }
+}
+`, testAccAgentID),
+				ExpectError: regexp.MustCompile(`Invalid Attribute Combination`),
+			},
+			{
+				Config: fmt.Sprintf(`
+data "domotz_devices" "test" {
+  agent_id = %d
+  filter {
+    user_data_type = "Toaster"
+  }
+}
+`, testAccAgentID),
+				ExpectError: regexp.MustCompile(`"Toaster" is not a known device type`),
+			},
+		},
+	})
+}
@@ -0,0 +1,102 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/domotz/terraform-provider-domotz/internal/client"
+)
+
+// deviceSelector finds devices by MAC address, IP address or display name.
+// Only the first non-empty field, in that order, is used.
+type deviceSelector struct {
+	hwAddress   string
+	ipAddress   string
+	displayName string
+}
+
+// matches reports whether d is selected
+func (s deviceSelector) matches(d client.Device) bool {
+	switch {
+	case s.hwAddress != "":
+		return d.HWAddress != "" && normalizeHWAddress(d.HWAddress) == normalizeHWAddress(s.hwAddress)
+	case s.ipAddress != "":
+		want := net.ParseIP(s.ipAddress)
+		for _, ip := range d.IPAddresses {
+			if ip == s.ipAddress || (want != nil && want.Equal(net.ParseIP(ip))) {
+				return true
+			}
+		}
+		return false
+	default:
+		return d.DisplayName == s.displayName
+	}
+}
+
+// String describes the selector for diagnostics, e.g. "IP address 10.0.0.1"
+func (s deviceSelector) String() string {
+	switch {
+	case s.hwAddress != "":
+		return "MAC address " + s.hwAddress
+	case s.ipAddress != "":
+		return "IP address " + s.ipAddress
+	default:
+		return fmt.Sprintf("display name %q", s.displayName)
+	}
+}
+
+// find lists the devices of every collector and returns those selected, with
+// AgentID set
+func (s deviceSelector) find(ctx context.Context, c *client.Client, agentIDs []int32) ([]client.Device, error) {
+	var matches []client.Device
+	for _, agentID := range agentIDs {
+		devices, err := c.ListDevices(ctx, agentID)
+		if err != nil {
+			return nil, err
+		}
+		for _, device := range devices {
+			if s.matches(device) {
+				device.AgentID = agentID
+				matches = append(matches, device)
+			}
+		}
+	}
+	return matches, nil
+}
+
+// only returns the single device in matches. scope says where they were
+// searched, e.g. "on agent 5", for the *client.NotFoundError returned when
+// there is none and the *ambiguousDeviceError returned when there are several.
+func (s deviceSelector) only(matches []client.Device, scope string) (*client.Device, error) {
+	switch len(matches) {
+	case 0:
+		return nil, &client.NotFoundError{Message: fmt.Sprintf("no device with %s %s", s, scope)}
+	case 1:
+		return &matches[0], nil
+	default:
+		return nil, &ambiguousDeviceError{selector: s, scope: scope, matches: matches}
+	}
+}
+
+// ambiguousDeviceError lists every device a selector matched when it must
+// match exactly one
+type ambiguousDeviceError struct {
+	selector deviceSelector
+	scope    string
+	matches  []client.Device
+}
+
+func (e *ambiguousDeviceError) Error() string {
+	candidates := make([]string, 0, len(e.matches))
+	for _, m := range e.matches {
+		candidates = append(candidates, fmt.Sprintf("  - device %d %q on agent %d", m.ID, m.DisplayName, m.AgentID))
+	}
+	return fmt.Sprintf("%d devices with %s %s:\n%s", len(e.matches), e.selector, e.scope, strings.Join(candidates, "\n"))
+}
+
+// normalizeHWAddress lowercases a MAC address and strips its separators
+func normalizeHWAddress(s string) string {
+	return strings.ToLower(strings.NewReplacer(":", "", "-", "", ".", "").Replace(s))
+}
@@ -0,0 +1,68 @@
+package provider
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/domotz/terraform-provider-domotz/internal/client"
+)
+
+func TestDeviceSelector_matches(t *testing.T) {
+	device := client.Device{
+		DisplayName: "Core Switch",
+		HWAddress:   "00:1A:2B:3C:4D:5E",
+		IPAddresses: []string{"10.0.0.2", "fe80::1"},
+	}
+
+	tests := []struct {
+		selector deviceSelector
+		want     bool
+	}{
+		{deviceSelector{displayName: "Core Switch"}, true},
+		{deviceSelector{displayName: "core switch"}, false},
+		{deviceSelector{displayName: "Core"}, false},
+		{deviceSelector{hwAddress: "00-1a-2b-3c-4d-5e"}, true},
+		{deviceSelector{ipAddress: "fe80:0::1"}, true},
+		{deviceSelector{ipAddress: "10.0.0.3"}, false},
+		// The MAC address wins over the display name
+		{deviceSelector{hwAddress: "00:00:00:00:00:01", displayName: "Core Switch"}, false},
+	}
+	for _, tt := range tests {
+		if got := tt.selector.matches(device); got != tt.want {
+			t.Errorf("%s: got %v, want %v", tt.selector, got, tt.want)
+		}
+	}
+}
+
+func TestDeviceSelector_only(t *testing.T) {
+	selector := deviceSelector{displayName: "gateway"}
+	matches := []client.Device{
+		{ID: 10, AgentID: 1, DisplayName: "gateway"},
+		{ID: 20, AgentID: 2, DisplayName: "gateway"},
+	}
+
+	device, err := selector.only(matches[:1], "on any collector")
+	if err != nil || device.ID != 10 {
+		t.Fatalf("Expected device 10, got %v, %v", device, err)
+	}
+
+	var notFound *client.NotFoundError
+	if _, err := selector.only(nil, "on agent 1"); !errors.As(err, &notFound) {
+		t.Errorf("Expected not found error, got %v", err)
+	} else if !strings.Contains(err.Error(), `no device with display name "gateway" on agent 1`) {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	_, err = selector.only(matches, "on any collector")
+	var ambiguous *ambiguousDeviceError
+	if !errors.As(err, &ambiguous) {
+		t.Fatalf("Expected ambiguous device error, got %v", err)
+	}
+	want := "2 devices with display name \"gateway\" on any collector:\n" +
+		"  - device 10 \"gateway\" on agent 1\n" +
+		"  - device 20 \"gateway\" on agent 2"
+	if err.Error() != want {
+		t.Errorf("Got error:\n%s\nwant:\n%s", err, want)
+	}
+}
@@ -0,0 +1,133 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/domotz/terraform-provider-domotz/internal/client"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+var (
+	_ basetypes.StringTypable                    = DeviceTypeType{}
+	_ basetypes.StringValuableWithSemanticEquals = DeviceTypeValue{}
+)
+
+// deviceTypeAliases maps the lower-cased ID, label and identifier of every
+// device type the provider has resolved to the type ID. Semantic equality has
+// no access to the client, but Create, Read and Update resolve the type
+// before the framework compares values, so the compared types are known here.
+var deviceTypeAliases sync.Map // string -> int32
+
+// rememberDeviceType records the spellings of a resolved device type
+func rememberDeviceType(deviceType *client.DeviceType) {
+	for _, alias := range []string{strconv.Itoa(int(deviceType.ID)), deviceType.Label, deviceType.Identifier} {
+		if alias != "" {
+			deviceTypeAliases.Store(strings.ToLower(alias), deviceType.ID)
+		}
+	}
+}
+
+// DeviceTypeType is a string type for user_data.type whose values are equal
+// when they name the same device type, e.g. "7", "router" and "Router"
+type DeviceTypeType struct {
+	basetypes.StringType
+}
+
+// Equal returns true if o is a DeviceTypeType
+func (t DeviceTypeType) Equal(o attr.Type) bool {
+	other, ok := o.(DeviceTypeType)
+	if !ok {
+		return false
+	}
+	return t.StringType.Equal(other.StringType)
+}
+
+// String returns a human readable name for the type
+func (t DeviceTypeType) String() string {
+	return "DeviceTypeType"
+}
+
+// ValueFromString wraps a StringValue in a DeviceTypeValue
+func (t DeviceTypeType) ValueFromString(_ context.Context, in basetypes.StringValue) (basetypes.StringValuable, diag.Diagnostics) {
+	return DeviceTypeValue{StringValue: in}, nil
+}
+
+// ValueFromTerraform converts a Terraform value to a DeviceTypeValue
+func (t DeviceTypeType) ValueFromTerraform(ctx context.Context, in tftypes.Value) (attr.Value, error) {
+	attrValue, err := t.StringType.ValueFromTerraform(ctx, in)
+	if err != nil {
+		return nil, err
+	}
+	stringValue, ok := attrValue.(basetypes.StringValue)
+	if !ok {
+		return nil, fmt.Errorf("unexpected value type of %T", attrValue)
+	}
+	return DeviceTypeValue{StringValue: stringValue}, nil
+}
+
+// ValueType returns the value type of DeviceTypeType
+func (t DeviceTypeType) ValueType(_ context.Context) attr.Value {
+	return DeviceTypeValue{}
+}
+
+// DeviceTypeValue is a device type given as a label or numeric ID
+type DeviceTypeValue struct {
+	basetypes.StringValue
+}
+
+// Equal returns true if o is a DeviceTypeValue with the same spelling
+func (v DeviceTypeValue) Equal(o attr.Value) bool {
+	other, ok := o.(DeviceTypeValue)
+	if !ok {
+		return false
+	}
+	return v.StringValue.Equal(other.StringValue)
+}
+
+// Type returns DeviceTypeType
+func (v DeviceTypeValue) Type(_ context.Context) attr.Type {
+	return DeviceTypeType{}
+}
+
+// StringSemanticEquals reports whether both values name the same device
+// type, so the spelling in the configuration is kept in state
+func (v DeviceTypeValue) StringSemanticEquals(_ context.Context, newValuable basetypes.StringValuable) (bool, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	newValue, ok := newValuable.(DeviceTypeValue)
+	if !ok {
+		diags.AddError("Semantic Equality Check Error",
+			fmt.Sprintf("Expected value type %T but got %T. Please report this to the provider developers.", v, newValuable))
+		return false, diags
+	}
+
+	prior, current := v.ValueString(), newValue.ValueString()
+	if strings.EqualFold(prior, current) {
+		return true, diags
+	}
+	priorID, ok := deviceTypeAliases.Load(strings.ToLower(prior))
+	if !ok {
+		return false, diags
+	}
+	currentID, ok := deviceTypeAliases.Load(strings.ToLower(current))
+	return ok && priorID == currentID, diags
+}
+
+// NewDeviceTypeValue returns a known DeviceTypeValue
+func NewDeviceTypeValue(value string) DeviceTypeValue {
+	return DeviceTypeValue{StringValue: basetypes.NewStringValue(value)}
+}
+
+// deviceTypeOrNull returns a null DeviceTypeValue for ""
+func deviceTypeOrNull(value string) DeviceTypeValue {
+	if value == "" {
+		return DeviceTypeValue{StringValue: basetypes.NewStringNull()}
+	}
+	return NewDeviceTypeValue(value)
+}
@@ -0,0 +1,35 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/domotz/terraform-provider-domotz/internal/client"
+)
+
+func TestDeviceTypeValue_StringSemanticEquals(t *testing.T) {
+	rememberDeviceType(&client.DeviceType{ID: 7, Label: "Router", Identifier: "ROUTER"})
+	rememberDeviceType(&client.DeviceType{ID: 24, Label: "Printer", Identifier: "PRINTER"})
+
+	tests := []struct {
+		prior, current string
+		want           bool
+	}{
+		{"Router", "Router", true},
+		{"router", "Router", true},
+		{"7", "Router", true},
+		{"Router", "7", true},
+		{"7", "Printer", false},
+		{"Toaster", "Router", false},
+		{"99", "99", true},
+	}
+	for _, tt := range tests {
+		got, diags := NewDeviceTypeValue(tt.prior).StringSemanticEquals(context.Background(), NewDeviceTypeValue(tt.current))
+		if diags.HasError() {
+			t.Fatalf("%q vs %q: unexpected diagnostics: %v", tt.prior, tt.current, diags)
+		}
+		if got != tt.want {
+			t.Errorf("%q vs %q: got %v, want %v", tt.prior, tt.current, got, tt.want)
+		}
+	}
+}
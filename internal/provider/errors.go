@@ -0,0 +1,28 @@
+package provider
+
+import (
+	"errors"
+
+	"github.com/domotz/terraform-provider-domotz/internal/client"
+)
+
+// apiErrorDetail renders an API error for a diagnostic detail, appending a
+// hint that matches the failure class so users know where to look
+func apiErrorDetail(err error) string {
+	detail := err.Error()
+	switch {
+	case errors.Is(err, client.ErrUnauthorized):
+		detail += "\n\nThe API key was rejected or lacks permission for this operation. " +
+			"Check the api_key provider argument or the DOMOTZ_API_KEY environment variable."
+	case errors.Is(err, client.ErrRateLimited):
+		detail += "\n\nThe Domotz API rate limit was exceeded. " +
+			"Reduce parallelism (terraform apply -parallelism=N) or retry later."
+	case errors.Is(err, client.ErrValidation):
+		detail += "\n\nThe Domotz API rejected the request payload. " +
+			"Check the configured values against the API documentation."
+	case errors.Is(err, client.ErrConflict):
+		detail += "\n\nThe object conflicts with one that already exists in Domotz. " +
+			"Import the existing object or remove it before applying."
+	}
+	return detail
+}
@@ -2,18 +2,30 @@ package provider
 
 import (
 	"context"
+	"fmt"
 	"os"
+	"time"
 
 	"github.com/domotz/terraform-provider-domotz/internal/client"
+	"github.com/hashicorp/terraform-plugin-framework-validators/float64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 )
 
 const (
 	defaultBaseURL = "https://api-eu-west-1-cell-1.domotz.com/public-api/v1"
+	defaultBurst   = 10
+
+	// defaultCassettePath is used by DOMOTZ_RECORD_MODE when DOMOTZ_CASSETTE is not set
+	defaultCassettePath = "domotz-cassette.yaml"
 )
 
 // Ensure the implementation satisfies the expected interfaces
@@ -28,8 +40,16 @@ type DomotzProvider struct {
 
 // DomotzProviderModel describes the provider data model
 type DomotzProviderModel struct {
-	APIKey  types.String `tfsdk:"api_key"`
-	BaseURL types.String `tfsdk:"base_url"`
+	APIKey            types.String  `tfsdk:"api_key"`
+	BaseURL           types.String  `tfsdk:"base_url"`
+	MaxRetries        types.Int64   `tfsdk:"max_retries"`
+	RetryMinBackoff   types.String  `tfsdk:"retry_min_backoff"`
+	RetryMaxBackoff   types.String  `tfsdk:"retry_max_backoff"`
+	RetryOnStatus     types.List    `tfsdk:"retry_on_status"`
+	RequestsPerSecond types.Float64 `tfsdk:"requests_per_second"`
+	Burst             types.Int64   `tfsdk:"burst"`
+	ReadCacheTTL      types.String  `tfsdk:"read_cache_ttl"`
+	BatchReads        types.Bool    `tfsdk:"batch_reads"`
 }
 
 // Metadata returns the provider type name
@@ -52,6 +72,51 @@ func (p *DomotzProvider) Schema(_ context.Context, _ provider.SchemaRequest, res
 				Description: "Base URL for the Domotz API. Defaults to production endpoint.",
 				Optional:    true,
 			},
+			"max_retries": schema.Int64Attribute{
+				Description: "Maximum number of retries for a failed API request. Defaults to 3.",
+				Optional:    true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(0),
+				},
+			},
+			"retry_min_backoff": schema.StringAttribute{
+				Description: "Base delay before the first retry, as a Go duration (e.g. \"500ms\"). Defaults to \"1s\".",
+				Optional:    true,
+			},
+			"retry_max_backoff": schema.StringAttribute{
+				Description: "Upper bound for the delay between retries, as a Go duration (e.g. \"1m\"). Defaults to \"30s\". A Retry-After header sent by the API takes precedence; a longer one fails the request instead of being waited out.",
+				Optional:    true,
+			},
+			"retry_on_status": schema.ListAttribute{
+				Description: "HTTP status codes that trigger a retry. Defaults to [429, 502, 503, 504]. Connection resets and timeouts are retried for GET, PUT and DELETE requests.",
+				Optional:    true,
+				ElementType: types.Int64Type,
+				Validators: []validator.List{
+					listvalidator.ValueInt64sAre(int64validator.Between(400, 599)),
+				},
+			},
+			"requests_per_second": schema.Float64Attribute{
+				Description: "Maximum sustained rate of API requests shared by all resources and data sources. Unlimited when not set.",
+				Optional:    true,
+				Validators: []validator.Float64{
+					float64validator.AtLeast(0.1),
+				},
+			},
+			"burst": schema.Int64Attribute{
+				Description: "Maximum number of API requests sent back-to-back and in flight at once when requests_per_second is set. Defaults to 10.",
+				Optional:    true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+			},
+			"read_cache_ttl": schema.StringAttribute{
+				Description: "Enables caching of API read responses for the given Go duration (e.g. \"5m\"), so resources that look up the same collection share one request per run. Writes invalidate the affected collection. Disabled when not set.",
+				Optional:    true,
+			},
+			"batch_reads": schema.BoolAttribute{
+				Description: "Serves domotz_device reads from one device list request per collector instead of one request per device. Defaults to false.",
+				Optional:    true,
+			},
 		},
 	}
 }
@@ -92,20 +157,112 @@ func (p *DomotzProvider) Configure(ctx context.Context, req provider.ConfigureRe
 
 	// Create API client
 	c := client.NewClient(baseURL, apiKey)
+	c.RetryPolicy = retryPolicyFromConfig(ctx, config, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !config.RequestsPerSecond.IsNull() {
+		burst := defaultBurst
+		if !config.Burst.IsNull() {
+			burst = int(config.Burst.ValueInt64())
+		}
+		c.SetRateLimit(config.RequestsPerSecond.ValueFloat64(), burst)
+	}
+
+	if !config.ReadCacheTTL.IsNull() {
+		ttl, err := time.ParseDuration(config.ReadCacheTTL.ValueString())
+		if err != nil || ttl <= 0 {
+			resp.Diagnostics.AddAttributeError(path.Root("read_cache_ttl"), "Invalid read_cache_ttl",
+				fmt.Sprintf("Expected a positive Go duration such as \"30s\" or \"5m\", got %q.", config.ReadCacheTTL.ValueString()))
+			return
+		}
+		c.SetCacheTTL(ttl)
+	}
+
+	c.SetBatchReads(config.BatchReads.ValueBool())
+
+	if mode := client.RecordMode(os.Getenv("DOMOTZ_RECORD_MODE")); mode != "" && mode != client.RecordModeOff {
+		cassettePath := os.Getenv("DOMOTZ_CASSETTE")
+		if cassettePath == "" {
+			cassettePath = defaultCassettePath
+		}
+		transport, err := client.NewCassetteTransport(cassettePath, mode, c.HTTPClient.Transport)
+		if err != nil {
+			resp.Diagnostics.AddError("Invalid DOMOTZ_RECORD_MODE", err.Error())
+			return
+		}
+		c.HTTPClient.Transport = transport
+	}
 
 	// Make the client available to resources and data sources
 	resp.DataSourceData = c
 	resp.ResourceData = c
 }
 
+// retryPolicyFromConfig overlays the configured retry attributes on the client defaults
+func retryPolicyFromConfig(ctx context.Context, config DomotzProviderModel, diags *diag.Diagnostics) client.RetryPolicy {
+	policy := client.DefaultRetryPolicy()
+
+	if !config.MaxRetries.IsNull() {
+		policy.MaxRetries = int(config.MaxRetries.ValueInt64())
+	}
+
+	if !config.RetryMinBackoff.IsNull() {
+		d, err := time.ParseDuration(config.RetryMinBackoff.ValueString())
+		if err != nil || d < 0 {
+			diags.AddAttributeError(path.Root("retry_min_backoff"), "Invalid retry_min_backoff",
+				fmt.Sprintf("Expected a non-negative Go duration such as \"500ms\" or \"2s\", got %q.", config.RetryMinBackoff.ValueString()))
+		}
+		policy.MinBackoff = d
+	}
+
+	if !config.RetryMaxBackoff.IsNull() {
+		d, err := time.ParseDuration(config.RetryMaxBackoff.ValueString())
+		if err != nil || d < 0 {
+			diags.AddAttributeError(path.Root("retry_max_backoff"), "Invalid retry_max_backoff",
+				fmt.Sprintf("Expected a non-negative Go duration such as \"30s\" or \"1m\", got %q.", config.RetryMaxBackoff.ValueString()))
+		}
+		policy.MaxBackoff = d
+	}
+
+	if policy.MaxBackoff < policy.MinBackoff {
+		diags.AddAttributeError(path.Root("retry_max_backoff"), "Invalid retry_max_backoff",
+			fmt.Sprintf("retry_max_backoff (%s) must not be less than retry_min_backoff (%s).", policy.MaxBackoff, policy.MinBackoff))
+	}
+
+	if !config.RetryOnStatus.IsNull() {
+		var statuses []int64
+		diags.Append(config.RetryOnStatus.ElementsAs(ctx, &statuses, false)...)
+		policy.RetryOnStatus = make([]int, 0, len(statuses))
+		for _, status := range statuses {
+			policy.RetryOnStatus = append(policy.RetryOnStatus, int(status))
+		}
+	}
+
+	return policy
+}
+
 // Resources defines the resources implemented in the provider
 func (p *DomotzProvider) Resources(_ context.Context) []func() resource.Resource {
 	return []func() resource.Resource{
 		NewDeviceResource,
 		NewCustomTagResource,
 		NewDeviceTagBindingResource,
+		NewDeviceTagsResource,
+		NewTagAssignmentResource,
 		NewSNMPSensorResource,
 		NewTCPSensorResource,
+		NewDiscoveredDeviceResource,
+		NewAlertProfileResource,
+		NewDeviceAlertProfileBindingResource,
+		NewAgentAlertProfileBindingResource,
+		NewWebhookContactResource,
+		NewAlertProfileContactBindingResource,
+		NewCustomDriverResource,
+		NewCustomDriverAssociationResource,
+		NewSNMPCredentialsResource,
+		NewAgentResource,
 	}
 }
 
@@ -113,9 +270,11 @@ func (p *DomotzProvider) Resources(_ context.Context) []func() resource.Resource
 func (p *DomotzProvider) DataSources(_ context.Context) []func() datasource.DataSource {
 	return []func() datasource.DataSource{
 		NewAgentDataSource,
+		NewAgentsDataSource,
 		NewDeviceDataSource,
 		NewDevicesDataSource,
 		NewDeviceVariablesDataSource,
+		NewDeviceTypesDataSource,
 	}
 }
 
@@ -1,22 +1,168 @@
 package provider
 
-// Placeholder file for future acceptance tests
-// When adding acceptance tests, uncomment and use the following:
-//
-// import (
-// 	"os"
-// 	"testing"
-//
-// 	"github.com/hashicorp/terraform-plugin-framework/providerserver"
-// 	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
-// )
-//
-// var testAccProtoV6ProviderFactories = map[string]func() (tfprotov6.ProviderServer, error){
-// 	"domotz": providerserver.NewProtocol6WithError(New("test")()),
-// }
-//
-// func testAccPreCheck(t *testing.T) {
-// 	if v := os.Getenv("DOMOTZ_API_KEY"); v == "" {
-// 		t.Fatal("DOMOTZ_API_KEY must be set for acceptance tests")
-// 	}
-// }
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/domotz/terraform-provider-domotz/internal/client"
+	"github.com/domotz/terraform-provider-domotz/internal/mockapi"
+	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"gopkg.in/yaml.v3"
+)
+
+// testAccAgentID is the collector seeded into every mock API server
+const testAccAgentID = 200891
+
+var testAccProtoV6ProviderFactories = map[string]func() (tfprotov6.ProviderServer, error){
+	"domotz": providerserver.NewProtocol6WithError(New("test")()),
+}
+
+// testAccMockServer starts an in-memory Domotz API seeded with one collector
+// and points the provider at it through DOMOTZ_API_KEY and DOMOTZ_BASE_URL,
+// so acceptance tests run without network access or a real account
+func testAccMockServer(t *testing.T) *mockapi.Server {
+	t.Helper()
+
+	server := mockapi.New()
+	t.Cleanup(server.Close)
+
+	server.AddAgent(client.Agent{
+		ID:          testAccAgentID,
+		DisplayName: "Test Collector",
+		Status: client.AgentStatus{
+			Value:      "ONLINE",
+			LastChange: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		},
+		Team:         client.Team{ID: 7, Name: "Operations"},
+		CreationTime: time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC),
+		OnlineAt:     time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+	})
+
+	t.Setenv("DOMOTZ_API_KEY", server.APIKey)
+	t.Setenv("DOMOTZ_BASE_URL", server.URL)
+	return server
+}
+
+func TestAccProvider_recordMode(t *testing.T) {
+	server := testAccMockServer(t)
+	cassette := filepath.Join(t.TempDir(), "cassette.yaml")
+	t.Setenv("DOMOTZ_RECORD_MODE", "record")
+	t.Setenv("DOMOTZ_CASSETTE", cassette)
+
+	// The collector is renamed between the steps, so the same requests get
+	// different answers in earlier and later sessions
+	steps := func(check func(step int) resource.TestCheckFunc) []resource.TestStep {
+		return []resource.TestStep{
+			{
+				Config: testAccAgentDataSourceConfig(testAccAgentID),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.domotz_agent.test", "display_name", "Test Collector"),
+					check(1),
+				),
+			},
+			{
+				PreConfig: func() {
+					if agent, ok := server.Agent(testAccAgentID); ok {
+						agent.DisplayName = "Renamed Collector"
+						server.UpdateAgent(agent)
+					}
+				},
+				Config: testAccAgentDataSourceConfig(testAccAgentID),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.domotz_agent.test", "display_name", "Renamed Collector"),
+					check(2),
+				),
+			},
+		}
+	}
+
+	// Every interaction is on disk by the time the step is checked, in
+	// sessions numbered from 1 in the order they were recorded
+	sessions := map[int]int{}
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: steps(func(step int) resource.TestCheckFunc {
+			return func(*terraform.State) error {
+				data, err := os.ReadFile(cassette)
+				if err != nil {
+					return err
+				}
+				if strings.Contains(string(data), server.APIKey) {
+					return fmt.Errorf("cassette leaks the API key:\n%s", data)
+				}
+				var recorded client.Cassette
+				if err := yaml.Unmarshal(data, &recorded); err != nil {
+					return err
+				}
+				last := 0
+				for _, in := range recorded.Interactions {
+					if in.Session < last || in.Session > last+1 {
+						return fmt.Errorf("session %d recorded after session %d:\n%s", in.Session, last, data)
+					}
+					last = in.Session
+				}
+				if !strings.Contains(string(data), fmt.Sprintf("/agent/%d", testAccAgentID)) {
+					return fmt.Errorf("agent request not recorded:\n%s", data)
+				}
+				sessions[step] = last
+				return nil
+			}
+		}),
+	})
+	if sessions[1] < 1 || sessions[2] <= sessions[1] {
+		t.Fatalf("expected each step to record new sessions, got %v", sessions)
+	}
+
+	// Replay without the API: each step is served its own sessions, in order
+	server.Close()
+	t.Setenv("DOMOTZ_RECORD_MODE", "replay")
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: steps(func(int) resource.TestCheckFunc {
+			return func(*terraform.State) error { return nil }
+		}),
+	})
+}
+
+// testAccCaptureID stores the numeric value of a resource attribute, so that
+// later steps can modify the object out of band
+func testAccCaptureID(name, attr string, dst *int32) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[name]
+		if !ok {
+			return fmt.Errorf("resource %s not found in state", name)
+		}
+		v, err := strconv.ParseInt(rs.Primary.Attributes[attr], 10, 32)
+		if err != nil {
+			return fmt.Errorf("%s.%s: %w", name, attr, err)
+		}
+		*dst = int32(v)
+		return nil
+	}
+}
+
+// testAccImportStateIDFunc builds a composite import ID from resource attributes
+func testAccImportStateIDFunc(name string, attrs ...string) resource.ImportStateIdFunc {
+	return func(s *terraform.State) (string, error) {
+		rs, ok := s.RootModule().Resources[name]
+		if !ok {
+			return "", fmt.Errorf("resource %s not found in state", name)
+		}
+		id := ""
+		for i, attr := range attrs {
+			if i > 0 {
+				id += ":"
+			}
+			id += rs.Primary.Attributes[attr]
+		}
+		return id, nil
+	}
+}
@@ -0,0 +1,361 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/domotz/terraform-provider-domotz/internal/client"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var (
+	_ resource.Resource                = &AgentResource{}
+	_ resource.ResourceWithImportState = &AgentResource{}
+)
+
+// NewAgentResource is a helper function to simplify the provider implementation
+func NewAgentResource() resource.Resource {
+	return &AgentResource{}
+}
+
+// AgentResource manages the settings of an existing collector
+type AgentResource struct {
+	client *client.Client
+}
+
+// AgentResourceModel describes the resource data model
+type AgentResourceModel struct {
+	ID                 types.String        `tfsdk:"id"`
+	AgentID            types.Int64         `tfsdk:"agent_id"`
+	DisplayName        types.String        `tfsdk:"display_name"`
+	Location           *AgentLocationModel `tfsdk:"location"`
+	TimeZone           types.String        `tfsdk:"timezone"`
+	TeamID             types.Int64         `tfsdk:"team_id"`
+	TeamName           types.String        `tfsdk:"team_name"`
+	Status             types.String        `tfsdk:"status"`
+	DeletionProtection types.Bool          `tfsdk:"deletion_protection"`
+}
+
+// AgentLocationModel describes the location block
+type AgentLocationModel struct {
+	Latitude  types.String `tfsdk:"latitude"`
+	Longitude types.String `tfsdk:"longitude"`
+}
+
+// Metadata returns the resource type name
+func (r *AgentResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_agent"
+}
+
+// Schema defines the schema for the resource
+func (r *AgentResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Manages the settings of an existing Domotz collector (agent). Collectors are installed on site and " +
+			"cannot be created through the API: the resource adopts one by ID. Arguments that are not set are left as they are in Domotz. " +
+			"Destroying the resource deletes the collector once deletion_protection is disabled.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Description: "Collector ID",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"agent_id": schema.Int64Attribute{
+				Description: "ID of the collector to adopt",
+				Required:    true,
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.RequiresReplace(),
+				},
+			},
+			"display_name": schema.StringAttribute{
+				Description: "Collector display name",
+				Optional:    true,
+				Computed:    true,
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"location": schema.SingleNestedAttribute{
+				Description: "Geographic location of the collector's site. Removing the block stops managing it.",
+				Optional:    true,
+				Attributes: map[string]schema.Attribute{
+					"latitude": schema.StringAttribute{
+						Description: "Latitude in decimal degrees",
+						Required:    true,
+					},
+					"longitude": schema.StringAttribute{
+						Description: "Longitude in decimal degrees",
+						Required:    true,
+					},
+				},
+			},
+			"timezone": schema.StringAttribute{
+				Description: "IANA time zone of the site (e.g., Europe/London)",
+				Optional:    true,
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"team_id": schema.Int64Attribute{
+				Description: "ID of the team the collector is assigned to",
+				Optional:    true,
+				Computed:    true,
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+				},
+			},
+			"team_name": schema.StringAttribute{
+				Description: "Name of the team the collector is assigned to",
+				Computed:    true,
+			},
+			"status": schema.StringAttribute{
+				Description: "Collector status (ONLINE, OFFLINE)",
+				Computed:    true,
+			},
+			"deletion_protection": schema.BoolAttribute{
+				Description: "Prevent Terraform from deleting the collector. Must be set to false, and applied, before " +
+					"the resource can be destroyed. Defaults to true.",
+				Optional: true,
+				Computed: true,
+				Default:  booldefault.StaticBool(true),
+			},
+		},
+	}
+}
+
+// Configure adds the provider configured client to the resource
+func (r *AgentResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	c, ok := req.ProviderData.(*client.Client)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *client.Client, got: %T", req.ProviderData),
+		)
+		return
+	}
+
+	r.client = c
+}
+
+// Create adopts the collector and applies the configured settings
+func (r *AgentResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan AgentResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	agentID := int32(plan.AgentID.ValueInt64())
+	agent, err := r.client.GetAgent(ctx, agentID)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error finding agent",
+			"Could not find agent: "+apiErrorDetail(err),
+		)
+		return
+	}
+
+	agent, err = r.apply(ctx, agent, plan)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error updating agent",
+			"Could not update agent: "+apiErrorDetail(err),
+		)
+		return
+	}
+
+	r.setState(&plan, agent)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+// Read refreshes the Terraform state with the latest data
+func (r *AgentResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state AgentResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	agent, err := r.client.GetAgent(ctx, int32(state.AgentID.ValueInt64()))
+	if err != nil {
+		var notFound *client.NotFoundError
+		if errors.As(err, &notFound) {
+			resp.State.RemoveResource(ctx)
+			return
+		}
+		resp.Diagnostics.AddError(
+			"Error reading agent",
+			"Could not read agent: "+apiErrorDetail(err),
+		)
+		return
+	}
+
+	r.setState(&state, agent)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+// Update applies changed settings to the collector
+func (r *AgentResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan AgentResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	agent, err := r.client.GetAgent(ctx, int32(plan.AgentID.ValueInt64()))
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error reading agent",
+			"Could not read agent: "+apiErrorDetail(err),
+		)
+		return
+	}
+
+	agent, err = r.apply(ctx, agent, plan)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error updating agent",
+			"Could not update agent: "+apiErrorDetail(err),
+		)
+		return
+	}
+
+	r.setState(&plan, agent)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+// Delete deletes the collector unless deletion_protection is enabled
+func (r *AgentResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state AgentResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if state.DeletionProtection.ValueBool() {
+		resp.Diagnostics.AddError(
+			"Agent is protected from deletion",
+			fmt.Sprintf("Cannot delete agent %d while deletion_protection is enabled. Set deletion_protection = false "+
+				"and apply before destroying it. Deleting a collector also deletes its devices and their history.",
+				state.AgentID.ValueInt64()),
+		)
+		return
+	}
+
+	err := r.client.DeleteAgent(ctx, int32(state.AgentID.ValueInt64()))
+	if err != nil {
+		var notFound *client.NotFoundError
+		if errors.As(err, &notFound) {
+			return
+		}
+		resp.Diagnostics.AddError(
+			"Error deleting agent",
+			"Could not delete agent: "+apiErrorDetail(err),
+		)
+		return
+	}
+}
+
+// ImportState imports the resource into Terraform state. Imported collectors
+// are protected from deletion until configured otherwise.
+func (r *AgentResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	agentID, err := strconv.ParseInt(req.ID, 10, 64)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Invalid import ID",
+			"Import ID must be the agent ID: "+err.Error(),
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("agent_id"), agentID)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), req.ID)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("deletion_protection"), true)...)
+}
+
+// apply sends the settings in plan that differ from agent and returns the
+// collector as updated
+func (r *AgentResource) apply(ctx context.Context, agent *client.Agent, plan AgentResourceModel) (*client.Agent, error) {
+	changed := false
+
+	if !plan.DisplayName.IsUnknown() && !plan.DisplayName.IsNull() && plan.DisplayName.ValueString() != agent.DisplayName {
+		if err := r.client.UpdateAgentDisplayName(ctx, agent.ID, plan.DisplayName.ValueString()); err != nil {
+			return nil, err
+		}
+		changed = true
+	}
+
+	if plan.Location != nil {
+		location := client.AgentLocation{
+			Latitude:  plan.Location.Latitude.ValueString(),
+			Longitude: plan.Location.Longitude.ValueString(),
+		}
+		if agent.Location == nil || *agent.Location != location {
+			if err := r.client.UpdateAgentLocation(ctx, agent.ID, location); err != nil {
+				return nil, err
+			}
+			changed = true
+		}
+	}
+
+	if !plan.TimeZone.IsUnknown() && !plan.TimeZone.IsNull() && plan.TimeZone.ValueString() != agent.TimeZone {
+		if err := r.client.UpdateAgentTimeZone(ctx, agent.ID, plan.TimeZone.ValueString()); err != nil {
+			return nil, err
+		}
+		changed = true
+	}
+
+	if !plan.TeamID.IsUnknown() && !plan.TeamID.IsNull() && int32(plan.TeamID.ValueInt64()) != agent.Team.ID {
+		if err := r.client.MoveAgentToTeam(ctx, agent.ID, int32(plan.TeamID.ValueInt64())); err != nil {
+			return nil, err
+		}
+		changed = true
+	}
+
+	if !changed {
+		return agent, nil
+	}
+	return r.client.GetAgent(ctx, agent.ID)
+}
+
+// setState copies the collector into the model. location is only refreshed
+// while it is managed.
+func (r *AgentResource) setState(m *AgentResourceModel, agent *client.Agent) {
+	m.ID = types.StringValue(strconv.Itoa(int(agent.ID)))
+	m.AgentID = types.Int64Value(int64(agent.ID))
+	m.DisplayName = types.StringValue(agent.DisplayName)
+	m.TimeZone = stringOrNull(agent.TimeZone)
+	m.TeamID = types.Int64Value(int64(agent.Team.ID))
+	m.TeamName = types.StringValue(agent.Team.Name)
+	m.Status = types.StringValue(agent.Status.Value)
+
+	if m.Location != nil {
+		m.Location = nil
+		if agent.Location != nil {
+			m.Location = &AgentLocationModel{
+				Latitude:  types.StringValue(agent.Location.Latitude),
+				Longitude: types.StringValue(agent.Location.Longitude),
+			}
+		}
+	}
+}
@@ -0,0 +1,204 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/domotz/terraform-provider-domotz/internal/client"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var (
+	_ resource.Resource                = &AgentAlertProfileBindingResource{}
+	_ resource.ResourceWithImportState = &AgentAlertProfileBindingResource{}
+)
+
+// NewAgentAlertProfileBindingResource is a helper function to simplify the provider implementation
+func NewAgentAlertProfileBindingResource() resource.Resource {
+	return &AgentAlertProfileBindingResource{}
+}
+
+// AgentAlertProfileBindingResource applies an alert profile to a collector
+type AgentAlertProfileBindingResource struct {
+	client *client.Client
+}
+
+// AgentAlertProfileBindingResourceModel describes the resource data model
+type AgentAlertProfileBindingResourceModel struct {
+	ID             types.String `tfsdk:"id"`
+	AgentID        types.Int64  `tfsdk:"agent_id"`
+	AlertProfileID types.Int64  `tfsdk:"alert_profile_id"`
+}
+
+// Metadata returns the resource type name
+func (r *AgentAlertProfileBindingResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_agent_alert_profile_binding"
+}
+
+// Schema defines the schema for the resource
+func (r *AgentAlertProfileBindingResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Applies an alert profile to a collector (agent) in Domotz.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Description: "Binding ID (format: agent_id:alert_profile_id)",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"agent_id": schema.Int64Attribute{
+				Description: "ID of the collector",
+				Required:    true,
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.RequiresReplace(),
+				},
+			},
+			"alert_profile_id": schema.Int64Attribute{
+				Description: "ID of the alert profile",
+				Required:    true,
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.RequiresReplace(),
+				},
+			},
+		},
+	}
+}
+
+// Configure adds the provider configured client to the resource
+func (r *AgentAlertProfileBindingResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	c, ok := req.ProviderData.(*client.Client)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *client.Client, got: %T", req.ProviderData),
+		)
+		return
+	}
+
+	r.client = c
+}
+
+// Create binds the alert profile to the collector
+func (r *AgentAlertProfileBindingResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan AgentAlertProfileBindingResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	agentID := int32(plan.AgentID.ValueInt64())
+	profileID := int32(plan.AlertProfileID.ValueInt64())
+
+	err := r.client.BindAlertProfileToAgent(ctx, agentID, profileID)
+	if err != nil {
+		resp.Diagnostics.AddError("Error binding alert profile to agent", apiErrorDetail(err))
+		return
+	}
+
+	plan.ID = types.StringValue(fmt.Sprintf("%d:%d", agentID, profileID))
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+// Read removes the binding from state when the profile is no longer applied
+func (r *AgentAlertProfileBindingResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state AgentAlertProfileBindingResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	agentID := int32(state.AgentID.ValueInt64())
+	profileID := int32(state.AlertProfileID.ValueInt64())
+
+	profiles, err := r.client.ListAgentAlertProfiles(ctx, agentID)
+	if err != nil {
+		var notFound *client.NotFoundError
+		if errors.As(err, &notFound) {
+			// The agent is gone, and the binding with it
+			resp.State.RemoveResource(ctx)
+			return
+		}
+		resp.Diagnostics.AddError("Error reading agent alert profiles", apiErrorDetail(err))
+		return
+	}
+
+	if !containsAlertProfile(profiles, profileID) {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+// Update is never called: every attribute requires replacement
+func (r *AgentAlertProfileBindingResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	resp.Diagnostics.AddError(
+		"Update not supported",
+		"Agent alert profile bindings cannot be updated. All changes require replacement.",
+	)
+}
+
+// Delete removes the alert profile from the collector
+func (r *AgentAlertProfileBindingResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state AgentAlertProfileBindingResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	agentID := int32(state.AgentID.ValueInt64())
+	profileID := int32(state.AlertProfileID.ValueInt64())
+
+	err := r.client.UnbindAlertProfileFromAgent(ctx, agentID, profileID)
+	if err != nil {
+		var notFound *client.NotFoundError
+		if errors.As(err, &notFound) {
+			return
+		}
+		resp.Diagnostics.AddError("Error unbinding alert profile from agent", apiErrorDetail(err))
+		return
+	}
+}
+
+// ImportState imports the resource into Terraform state
+func (r *AgentAlertProfileBindingResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	// Import format: "agent_id:alert_profile_id"
+	parts := strings.Split(req.ID, ":")
+	if len(parts) != 2 {
+		resp.Diagnostics.AddError(
+			"Invalid import ID",
+			"Import ID must be in the format 'agent_id:alert_profile_id'",
+		)
+		return
+	}
+
+	agentID, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid agent ID", err.Error())
+		return
+	}
+
+	profileID, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid alert profile ID", err.Error())
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("agent_id"), agentID)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("alert_profile_id"), profileID)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), req.ID)...)
+}
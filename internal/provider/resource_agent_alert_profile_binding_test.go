@@ -0,0 +1,64 @@
+package provider
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+)
+
+func TestAccAgentAlertProfileBindingResource(t *testing.T) {
+	server := testAccMockServer(t)
+	var profileID int32
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy: func(s *terraform.State) error {
+			if ids := server.AgentAlertProfileIDs(testAccAgentID); len(ids) != 0 {
+				return fmt.Errorf("agent still has alert profiles applied: %v", ids)
+			}
+			return nil
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: testAccAgentAlertProfileBindingConfig(),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrPair("domotz_agent_alert_profile_binding.test", "alert_profile_id", "domotz_alert_profile.test", "id"),
+					testAccCaptureID("domotz_alert_profile.test", "id", &profileID),
+					func(*terraform.State) error {
+						if ids := server.AgentAlertProfileIDs(testAccAgentID); len(ids) != 1 || ids[0] != profileID {
+							return fmt.Errorf("expected alert profile %d applied in API, got %v", profileID, ids)
+						}
+						return nil
+					},
+				),
+			},
+			{
+				ResourceName:      "domotz_agent_alert_profile_binding.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			{
+				PreConfig:          func() { server.UnbindAgentAlertProfile(testAccAgentID, profileID) },
+				Config:             testAccAgentAlertProfileBindingConfig(),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+		},
+	})
+}
+
+func testAccAgentAlertProfileBindingConfig() string {
+	return fmt.Sprintf(`
+resource "domotz_alert_profile" "test" {
+  name   = "Collector health"
+  events = ["device_status_down", "device_status_up"]
+}
+
+resource "domotz_agent_alert_profile_binding" "test" {
+  agent_id         = %d
+  alert_profile_id = domotz_alert_profile.test.id
+}
+`, testAccAgentID)
+}
@@ -0,0 +1,130 @@
+package provider
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"testing"
+
+	"github.com/domotz/terraform-provider-domotz/internal/client"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+)
+
+// testAccSiteAgentID is a collector the agent resource tests may delete
+const testAccSiteAgentID = 300017
+
+func TestAccAgentResource(t *testing.T) {
+	server := testAccMockServer(t)
+	server.AddAgent(client.Agent{
+		ID:          testAccSiteAgentID,
+		DisplayName: "Raspberry Pi",
+		Status:      client.AgentStatus{Value: "ONLINE"},
+		Team:        client.Team{ID: 7, Name: "Operations"},
+	})
+	server.AddTeam(client.Team{ID: 8, Name: "Field Sites"})
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy: func(s *terraform.State) error {
+			if _, ok := server.Agent(testAccSiteAgentID); ok {
+				return fmt.Errorf("agent %d still exists", testAccSiteAgentID)
+			}
+			return nil
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: testAccAgentResourceConfig("Acme Leeds", "Europe/London", 8, true),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("domotz_agent.test", "id", strconv.Itoa(testAccSiteAgentID)),
+					resource.TestCheckResourceAttr("domotz_agent.test", "display_name", "Acme Leeds"),
+					resource.TestCheckResourceAttr("domotz_agent.test", "timezone", "Europe/London"),
+					resource.TestCheckResourceAttr("domotz_agent.test", "team_id", "8"),
+					resource.TestCheckResourceAttr("domotz_agent.test", "team_name", "Field Sites"),
+					resource.TestCheckResourceAttr("domotz_agent.test", "location.latitude", "53.7997"),
+					resource.TestCheckResourceAttr("domotz_agent.test", "status", "ONLINE"),
+					resource.TestCheckResourceAttr("domotz_agent.test", "deletion_protection", "true"),
+					func(*terraform.State) error {
+						agent, _ := server.Agent(testAccSiteAgentID)
+						if agent.DisplayName != "Acme Leeds" || agent.Team.ID != 8 || agent.Location == nil {
+							return fmt.Errorf("expected settings to reach the API, got %+v", agent)
+						}
+						return nil
+					},
+				),
+			},
+			{
+				// location is only managed once configured
+				ResourceName:            "domotz_agent.test",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"location"},
+			},
+			{
+				PreConfig: func() {
+					agent, _ := server.Agent(testAccSiteAgentID)
+					agent.DisplayName = "Renamed in UI"
+					server.UpdateAgent(agent)
+				},
+				Config:             testAccAgentResourceConfig("Acme Leeds", "Europe/London", 8, true),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				Config: testAccAgentResourceConfig("Acme York", "Europe/Dublin", 7, true),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("domotz_agent.test", "display_name", "Acme York"),
+					resource.TestCheckResourceAttr("domotz_agent.test", "timezone", "Europe/Dublin"),
+					resource.TestCheckResourceAttr("domotz_agent.test", "team_name", "Operations"),
+				),
+			},
+			{
+				Config:      testAccAgentResourceConfig("Acme York", "Europe/Dublin", 7, true),
+				Destroy:     true,
+				ExpectError: regexp.MustCompile(`deletion_protection is enabled`),
+			},
+			{
+				Config: testAccAgentResourceConfig("Acme York", "Europe/Dublin", 7, false),
+				Check: func(*terraform.State) error {
+					if _, ok := server.Agent(testAccSiteAgentID); !ok {
+						return fmt.Errorf("agent %d was deleted while protected", testAccSiteAgentID)
+					}
+					return nil
+				},
+			},
+		},
+	})
+}
+
+func TestAccAgentResource_invalidTimeZone(t *testing.T) {
+	server := testAccMockServer(t)
+	server.AddAgent(client.Agent{ID: testAccSiteAgentID, DisplayName: "Raspberry Pi", Team: client.Team{ID: 7, Name: "Operations"}})
+	server.AddTeam(client.Team{ID: 8, Name: "Field Sites"})
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccAgentResourceConfig("Acme Leeds", "Mars/Olympus_Mons", 8, false),
+				ExpectError: regexp.MustCompile(`invalid timezone`),
+			},
+		},
+	})
+}
+
+func testAccAgentResourceConfig(displayName, timeZone string, teamID int, deletionProtection bool) string {
+	return fmt.Sprintf(`
+resource "domotz_agent" "test" {
+  agent_id            = %d
+  display_name        = %q
+  timezone            = %q
+  team_id             = %d
+  deletion_protection = %t
+
+  location = {
+    latitude  = "53.7997"
+    longitude = "-1.5492"
+  }
+}
+`, testAccSiteAgentID, displayName, timeZone, teamID, deletionProtection)
+}
@@ -0,0 +1,334 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/domotz/terraform-provider-domotz/internal/client"
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/setvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var (
+	_ resource.Resource                   = &AlertProfileResource{}
+	_ resource.ResourceWithImportState    = &AlertProfileResource{}
+	_ resource.ResourceWithValidateConfig = &AlertProfileResource{}
+)
+
+// NewAlertProfileResource is a helper function to simplify the provider implementation
+func NewAlertProfileResource() resource.Resource {
+	return &AlertProfileResource{}
+}
+
+// AlertProfileResource defines the resource implementation
+type AlertProfileResource struct {
+	client *client.Client
+}
+
+// AlertProfileResourceModel describes the resource data model
+type AlertProfileResourceModel struct {
+	ID                 types.String                 `tfsdk:"id"`
+	Name               types.String                 `tfsdk:"name"`
+	Description        types.String                 `tfsdk:"description"`
+	Events             types.Set                    `tfsdk:"events"`
+	VariableThresholds []AlertProfileThresholdModel `tfsdk:"variable_thresholds"`
+}
+
+// AlertProfileThresholdModel describes one entry of variable_thresholds
+type AlertProfileThresholdModel struct {
+	Variable types.String `tfsdk:"variable"`
+	Operator types.String `tfsdk:"operator"`
+	Value    types.String `tfsdk:"value"`
+}
+
+// Metadata returns the resource type name
+func (r *AlertProfileResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_alert_profile"
+}
+
+// Schema defines the schema for the resource
+func (r *AlertProfileResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Manages an alert profile in Domotz: the events that trigger notifications " +
+			"for the agents and devices bound to it.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Description: "Alert profile ID",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"name": schema.StringAttribute{
+				Description: "Alert profile name",
+				Required:    true,
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+			"description": schema.StringAttribute{
+				Description: "Free-text description of the alert profile",
+				Optional:    true,
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+			"events": schema.SetAttribute{
+				Description: "Events that trigger an alert (" + strings.Join(client.AlertProfileEvents, ", ") + ")",
+				Required:    true,
+				ElementType: types.StringType,
+				Validators: []validator.Set{
+					setvalidator.SizeAtLeast(1),
+					setvalidator.ValueStringsAre(stringvalidator.OneOf(client.AlertProfileEvents...)),
+				},
+			},
+			"variable_thresholds": schema.ListNestedAttribute{
+				Description: "Conditions on device variables that raise the " + client.AlertEventDeviceVariableThreshold + " event, which events must include",
+				Optional:    true,
+				Validators: []validator.List{
+					listvalidator.SizeAtLeast(1),
+				},
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"variable": schema.StringAttribute{
+							Description: "Label of the device variable, e.g. \"CPU Usage\"",
+							Required:    true,
+							Validators: []validator.String{
+								stringvalidator.LengthAtLeast(1),
+							},
+						},
+						"operator": schema.StringAttribute{
+							Description: "Comparison with value (" + strings.Join(client.AlertThresholdOperators, ", ") + ")",
+							Required:    true,
+							Validators: []validator.String{
+								stringvalidator.OneOf(client.AlertThresholdOperators...),
+							},
+						},
+						"value": schema.StringAttribute{
+							Description: "Value the variable is compared with",
+							Required:    true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// ValidateConfig checks that variable_thresholds come with the event they raise
+func (r *AlertProfileResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config AlertProfileResourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() || len(config.VariableThresholds) == 0 || config.Events.IsUnknown() || config.Events.IsNull() {
+		return
+	}
+
+	var events []types.String
+	resp.Diagnostics.Append(config.Events.ElementsAs(ctx, &events, false)...)
+	for _, e := range events {
+		if e.IsUnknown() || e.ValueString() == client.AlertEventDeviceVariableThreshold {
+			return
+		}
+	}
+	resp.Diagnostics.AddAttributeError(path.Root("variable_thresholds"), "Missing alert profile event",
+		fmt.Sprintf("variable_thresholds require %q in events.", client.AlertEventDeviceVariableThreshold))
+}
+
+// Configure adds the provider configured client to the resource
+func (r *AlertProfileResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	c, ok := req.ProviderData.(*client.Client)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *client.Client, got: %T", req.ProviderData),
+		)
+		return
+	}
+
+	r.client = c
+}
+
+// Create creates the resource and sets the initial Terraform state
+func (r *AlertProfileResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan AlertProfileResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var events []string
+	resp.Diagnostics.Append(plan.Events.ElementsAs(ctx, &events, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	profile, err := r.client.CreateAlertProfile(ctx, client.CreateAlertProfileRequest{
+		Name:        plan.Name.ValueString(),
+		Description: plan.Description.ValueString(),
+		Events:      events,
+		Thresholds:  alertProfileThresholds(plan.VariableThresholds),
+	})
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error creating alert profile",
+			"Could not create alert profile: "+apiErrorDetail(err),
+		)
+		return
+	}
+
+	plan.ID = types.StringValue(strconv.Itoa(int(profile.ID)))
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+// Read refreshes the Terraform state with the latest data
+func (r *AlertProfileResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state AlertProfileResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	profileID, err := strconv.ParseInt(state.ID.ValueString(), 10, 32)
+	if err != nil {
+		resp.Diagnostics.AddError("Error parsing alert profile ID", err.Error())
+		return
+	}
+
+	profile, err := r.client.GetAlertProfile(ctx, int32(profileID))
+	if err != nil {
+		var notFound *client.NotFoundError
+		if errors.As(err, &notFound) {
+			resp.State.RemoveResource(ctx)
+			return
+		}
+		resp.Diagnostics.AddError(
+			"Error reading alert profile",
+			"Could not read alert profile: "+apiErrorDetail(err),
+		)
+		return
+	}
+
+	events, diags := types.SetValueFrom(ctx, types.StringType, profile.Events)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	state.Name = types.StringValue(profile.Name)
+	state.Description = stringOrNull(profile.Description)
+	state.Events = events
+	state.VariableThresholds = nil
+	for _, th := range profile.Thresholds {
+		state.VariableThresholds = append(state.VariableThresholds, AlertProfileThresholdModel{
+			Variable: types.StringValue(th.Variable),
+			Operator: types.StringValue(th.Operator),
+			Value:    types.StringValue(th.Value),
+		})
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+// Update updates the resource and sets the updated Terraform state on success
+func (r *AlertProfileResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan AlertProfileResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	profileID, err := strconv.ParseInt(plan.ID.ValueString(), 10, 32)
+	if err != nil {
+		resp.Diagnostics.AddError("Error parsing alert profile ID", err.Error())
+		return
+	}
+
+	var events []string
+	resp.Diagnostics.Append(plan.Events.ElementsAs(ctx, &events, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// An empty description, or no thresholds, clears them
+	name := plan.Name.ValueString()
+	description := plan.Description.ValueString()
+	thresholds := alertProfileThresholds(plan.VariableThresholds)
+	if thresholds == nil {
+		thresholds = []client.AlertProfileThreshold{}
+	}
+	_, err = r.client.UpdateAlertProfile(ctx, int32(profileID), client.UpdateAlertProfileRequest{
+		Name:        &name,
+		Description: &description,
+		Events:      events,
+		Thresholds:  thresholds,
+	})
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error updating alert profile",
+			"Could not update alert profile: "+apiErrorDetail(err),
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+// Delete deletes the resource and removes the Terraform state on success
+func (r *AlertProfileResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state AlertProfileResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	profileID, err := strconv.ParseInt(state.ID.ValueString(), 10, 32)
+	if err != nil {
+		resp.Diagnostics.AddError("Error parsing alert profile ID", err.Error())
+		return
+	}
+
+	err = r.client.DeleteAlertProfile(ctx, int32(profileID))
+	if err != nil {
+		var notFound *client.NotFoundError
+		if errors.As(err, &notFound) {
+			return
+		}
+		resp.Diagnostics.AddError(
+			"Error deleting alert profile",
+			"Could not delete alert profile: "+apiErrorDetail(err),
+		)
+		return
+	}
+}
+
+// ImportState imports the resource into Terraform state
+func (r *AlertProfileResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+}
+
+func alertProfileThresholds(models []AlertProfileThresholdModel) []client.AlertProfileThreshold {
+	var thresholds []client.AlertProfileThreshold
+	for _, m := range models {
+		thresholds = append(thresholds, client.AlertProfileThreshold{
+			Variable: m.Variable.ValueString(),
+			Operator: m.Operator.ValueString(),
+			Value:    m.Value.ValueString(),
+		})
+	}
+	return thresholds
+}
@@ -0,0 +1,211 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/domotz/terraform-provider-domotz/internal/client"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var (
+	_ resource.Resource                = &AlertProfileContactBindingResource{}
+	_ resource.ResourceWithImportState = &AlertProfileContactBindingResource{}
+)
+
+// NewAlertProfileContactBindingResource is a helper function to simplify the provider implementation
+func NewAlertProfileContactBindingResource() resource.Resource {
+	return &AlertProfileContactBindingResource{}
+}
+
+// AlertProfileContactBindingResource routes the alerts of a profile to a contact
+type AlertProfileContactBindingResource struct {
+	client *client.Client
+}
+
+// AlertProfileContactBindingResourceModel describes the resource data model
+type AlertProfileContactBindingResourceModel struct {
+	ID             types.String `tfsdk:"id"`
+	AlertProfileID types.Int64  `tfsdk:"alert_profile_id"`
+	ContactID      types.Int64  `tfsdk:"contact_id"`
+}
+
+// Metadata returns the resource type name
+func (r *AlertProfileContactBindingResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_alert_profile_contact_binding"
+}
+
+// Schema defines the schema for the resource
+func (r *AlertProfileContactBindingResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Delivers the alerts of an alert profile to a contact in Domotz.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Description: "Binding ID (format: alert_profile_id:contact_id)",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"alert_profile_id": schema.Int64Attribute{
+				Description: "ID of the alert profile",
+				Required:    true,
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.RequiresReplace(),
+				},
+			},
+			"contact_id": schema.Int64Attribute{
+				Description: "ID of the contact",
+				Required:    true,
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.RequiresReplace(),
+				},
+			},
+		},
+	}
+}
+
+// Configure adds the provider configured client to the resource
+func (r *AlertProfileContactBindingResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	c, ok := req.ProviderData.(*client.Client)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *client.Client, got: %T", req.ProviderData),
+		)
+		return
+	}
+
+	r.client = c
+}
+
+// Create binds the contact to the alert profile
+func (r *AlertProfileContactBindingResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan AlertProfileContactBindingResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	profileID := int32(plan.AlertProfileID.ValueInt64())
+	contactID := int32(plan.ContactID.ValueInt64())
+
+	err := r.client.BindContactToAlertProfile(ctx, profileID, contactID)
+	if err != nil {
+		resp.Diagnostics.AddError("Error binding contact to alert profile", apiErrorDetail(err))
+		return
+	}
+
+	plan.ID = types.StringValue(fmt.Sprintf("%d:%d", profileID, contactID))
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+// Read removes the binding from state when the contact is no longer bound
+func (r *AlertProfileContactBindingResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state AlertProfileContactBindingResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	profileID := int32(state.AlertProfileID.ValueInt64())
+	contactID := int32(state.ContactID.ValueInt64())
+
+	contacts, err := r.client.ListAlertProfileContacts(ctx, profileID)
+	if err != nil {
+		var notFound *client.NotFoundError
+		if errors.As(err, &notFound) {
+			// The alert profile is gone, and the binding with it
+			resp.State.RemoveResource(ctx)
+			return
+		}
+		resp.Diagnostics.AddError("Error reading alert profile contacts", apiErrorDetail(err))
+		return
+	}
+
+	found := false
+	for _, contact := range contacts {
+		if contact.ID == contactID {
+			found = true
+			break
+		}
+	}
+	if !found {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+// Update is never called: every attribute requires replacement
+func (r *AlertProfileContactBindingResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	resp.Diagnostics.AddError(
+		"Update not supported",
+		"Alert profile contact bindings cannot be updated. All changes require replacement.",
+	)
+}
+
+// Delete unbinds the contact from the alert profile
+func (r *AlertProfileContactBindingResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state AlertProfileContactBindingResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	profileID := int32(state.AlertProfileID.ValueInt64())
+	contactID := int32(state.ContactID.ValueInt64())
+
+	err := r.client.UnbindContactFromAlertProfile(ctx, profileID, contactID)
+	if err != nil {
+		var notFound *client.NotFoundError
+		if errors.As(err, &notFound) {
+			return
+		}
+		resp.Diagnostics.AddError("Error unbinding contact from alert profile", apiErrorDetail(err))
+		return
+	}
+}
+
+// ImportState imports the resource into Terraform state
+func (r *AlertProfileContactBindingResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	// Import format: "alert_profile_id:contact_id"
+	parts := strings.Split(req.ID, ":")
+	if len(parts) != 2 {
+		resp.Diagnostics.AddError(
+			"Invalid import ID",
+			"Import ID must be in the format 'alert_profile_id:contact_id'",
+		)
+		return
+	}
+
+	profileID, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid alert profile ID", err.Error())
+		return
+	}
+
+	contactID, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid contact ID", err.Error())
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("alert_profile_id"), profileID)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("contact_id"), contactID)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), req.ID)...)
+}
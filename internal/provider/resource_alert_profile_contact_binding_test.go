@@ -0,0 +1,71 @@
+package provider
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+)
+
+func TestAccAlertProfileContactBindingResource(t *testing.T) {
+	server := testAccMockServer(t)
+	var profileID, contactID int32
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy: func(s *terraform.State) error {
+			if ids := server.AlertProfileContactIDs(profileID); len(ids) != 0 {
+				return fmt.Errorf("alert profile still delivers to contacts: %v", ids)
+			}
+			return nil
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: testAccAlertProfileContactBindingConfig(),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrPair("domotz_alert_profile_contact_binding.test", "alert_profile_id", "domotz_alert_profile.test", "id"),
+					resource.TestCheckResourceAttrPair("domotz_alert_profile_contact_binding.test", "contact_id", "domotz_webhook_contact.test", "id"),
+					testAccCaptureID("domotz_alert_profile.test", "id", &profileID),
+					testAccCaptureID("domotz_webhook_contact.test", "id", &contactID),
+					func(*terraform.State) error {
+						if ids := server.AlertProfileContactIDs(profileID); len(ids) != 1 || ids[0] != contactID {
+							return fmt.Errorf("expected contact %d bound in API, got %v", contactID, ids)
+						}
+						return nil
+					},
+				),
+			},
+			{
+				ResourceName:      "domotz_alert_profile_contact_binding.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			{
+				PreConfig:          func() { server.UnbindAlertProfileContact(profileID, contactID) },
+				Config:             testAccAlertProfileContactBindingConfig(),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+		},
+	})
+}
+
+func testAccAlertProfileContactBindingConfig() string {
+	return `
+resource "domotz_alert_profile" "test" {
+  name   = "Core network"
+  events = ["device_status_down"]
+}
+
+resource "domotz_webhook_contact" "test" {
+  name = "Slack #noc"
+  url  = "https://hooks.slack.com/services/T000/B000/XXXX"
+}
+
+resource "domotz_alert_profile_contact_binding" "test" {
+  alert_profile_id = domotz_alert_profile.test.id
+  contact_id       = domotz_webhook_contact.test.id
+}
+`
+}
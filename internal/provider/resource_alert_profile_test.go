@@ -0,0 +1,127 @@
+package provider
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+)
+
+func TestAccAlertProfileResource(t *testing.T) {
+	server := testAccMockServer(t)
+	var profileID int32
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy: func(s *terraform.State) error {
+			if _, ok := server.AlertProfile(profileID); ok {
+				return fmt.Errorf("alert profile %d still exists", profileID)
+			}
+			return nil
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: testAccAlertProfileConfig("Core network", `description = "Pages the NOC"`, `"device_status_down", "device_ip_change"`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrSet("domotz_alert_profile.test", "id"),
+					resource.TestCheckResourceAttr("domotz_alert_profile.test", "name", "Core network"),
+					resource.TestCheckResourceAttr("domotz_alert_profile.test", "description", "Pages the NOC"),
+					resource.TestCheckResourceAttr("domotz_alert_profile.test", "events.#", "2"),
+					resource.TestCheckTypeSetElemAttr("domotz_alert_profile.test", "events.*", "device_ip_change"),
+					testAccCaptureID("domotz_alert_profile.test", "id", &profileID),
+				),
+			},
+			{
+				ResourceName:      "domotz_alert_profile.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			{
+				// Rename, drop the description, change the events and add thresholds in place
+				Config: testAccAlertProfileConfig("Core", testAccAlertProfileThresholds, `"device_status_down", "device_variable_threshold", "device_snmp_eye_trigger"`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("domotz_alert_profile.test", "name", "Core"),
+					resource.TestCheckNoResourceAttr("domotz_alert_profile.test", "description"),
+					resource.TestCheckResourceAttr("domotz_alert_profile.test", "events.#", "3"),
+					resource.TestCheckResourceAttr("domotz_alert_profile.test", "variable_thresholds.#", "2"),
+					resource.TestCheckResourceAttr("domotz_alert_profile.test", "variable_thresholds.0.variable", "CPU Usage"),
+					resource.TestCheckResourceAttr("domotz_alert_profile.test", "variable_thresholds.1.operator", "equal"),
+					func(*terraform.State) error {
+						p, ok := server.AlertProfile(profileID)
+						if !ok || p.Name != "Core" || p.Description != "" || len(p.Events) != 3 || len(p.Thresholds) != 2 {
+							return fmt.Errorf("unexpected alert profile in API: %+v", p)
+						}
+						return nil
+					},
+				),
+			},
+			{
+				ResourceName:      "domotz_alert_profile.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			{
+				// Removing the thresholds clears them
+				Config: testAccAlertProfileConfig("Core", "", `"device_status_down", "device_variable_threshold", "device_snmp_eye_trigger"`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckNoResourceAttr("domotz_alert_profile.test", "variable_thresholds.#"),
+					func(*terraform.State) error {
+						if p, _ := server.AlertProfile(profileID); len(p.Thresholds) != 0 {
+							return fmt.Errorf("expected thresholds to be cleared, got %+v", p.Thresholds)
+						}
+						return nil
+					},
+				),
+			},
+			{
+				PreConfig: func() {
+					p, _ := server.AlertProfile(profileID)
+					p.Events = []string{"device_status_up"}
+					server.UpdateAlertProfile(p)
+				},
+				Config:             testAccAlertProfileConfig("Core", "", `"device_status_down", "device_variable_threshold", "device_snmp_eye_trigger"`),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+		},
+	})
+}
+
+func TestAccAlertProfileResource_invalidEvent(t *testing.T) {
+	testAccMockServer(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccAlertProfileConfig("Core", "", `"device_on_fire"`),
+				ExpectError: regexp.MustCompile(`value must be one of`),
+			},
+			{
+				Config:      testAccAlertProfileConfig("Core", testAccAlertProfileThresholds, `"device_status_down"`),
+				ExpectError: regexp.MustCompile(`variable_thresholds require "device_variable_threshold" in events`),
+			},
+			{
+				Config:      testAccAlertProfileConfig("Core", `description = ""`, `"device_status_down"`),
+				ExpectError: regexp.MustCompile(`string length must be at least 1`),
+			},
+		},
+	})
+}
+
+const testAccAlertProfileThresholds = `variable_thresholds = [
+    { variable = "CPU Usage", operator = "greater_than", value = "90" },
+    { variable = "Fan Status", operator = "equal", value = "FAILED" },
+  ]`
+
+func testAccAlertProfileConfig(name, description, events string) string {
+	return fmt.Sprintf(`
+resource "domotz_alert_profile" "test" {
+  name   = %q
+  %s
+  events = [%s]
+}
+`, name, description, events)
+}
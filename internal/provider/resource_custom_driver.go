@@ -0,0 +1,308 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/domotz/terraform-provider-domotz/internal/client"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64default"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var (
+	_ resource.Resource                = &CustomDriverResource{}
+	_ resource.ResourceWithImportState = &CustomDriverResource{}
+)
+
+// customDriverSamplePeriods are the sample periods, in seconds, Domotz accepts
+var customDriverSamplePeriods = []int64{60, 300, 600, 900, 1800, 3600, 7200, 14400, 43200, 86400}
+
+// NewCustomDriverResource is a helper function to simplify the provider implementation
+func NewCustomDriverResource() resource.Resource {
+	return &CustomDriverResource{}
+}
+
+// CustomDriverResource defines the resource implementation
+type CustomDriverResource struct {
+	client *client.Client
+}
+
+// CustomDriverResourceModel describes the resource data model
+type CustomDriverResourceModel struct {
+	ID                  types.String                 `tfsdk:"id"`
+	Name                types.String                 `tfsdk:"name"`
+	Description         types.String                 `tfsdk:"description"`
+	Script              types.String                 `tfsdk:"script"`
+	MinimalSamplePeriod types.Int64                  `tfsdk:"minimal_sample_period"`
+	Parameters          []CustomDriverParameterModel `tfsdk:"parameters"`
+}
+
+// CustomDriverParameterModel describes one entry of parameters
+type CustomDriverParameterModel struct {
+	Name         types.String `tfsdk:"name"`
+	Description  types.String `tfsdk:"description"`
+	ValueType    types.String `tfsdk:"value_type"`
+	DefaultValue types.String `tfsdk:"default_value"`
+}
+
+// Metadata returns the resource type name
+func (r *CustomDriverResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_custom_driver"
+}
+
+// Schema defines the schema for the resource
+func (r *CustomDriverResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Manages a Domotz custom driver, a JavaScript integration that runs on the " +
+			"collector and produces device variables.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Description: "Custom driver ID",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"name": schema.StringAttribute{
+				Description: "Custom driver name",
+				Required:    true,
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+			"description": schema.StringAttribute{
+				Description: "Free-text description of the custom driver",
+				Optional:    true,
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+			"script": schema.StringAttribute{
+				Description: "JavaScript source of the driver, usually read with file()",
+				Required:    true,
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+			"minimal_sample_period": schema.Int64Attribute{
+				Description: "Shortest interval, in seconds, at which the driver may run (60, 300, 600, 900, 1800, 3600, 7200, 14400, 43200 or 86400). Defaults to 300.",
+				Optional:    true,
+				Computed:    true,
+				Default:     int64default.StaticInt64(300),
+				Validators: []validator.Int64{
+					int64validator.OneOf(customDriverSamplePeriods...),
+				},
+			},
+			"parameters": schema.ListNestedAttribute{
+				Description: "Inputs the driver reads through D.getParameter(), set per device by domotz_custom_driver_association",
+				Optional:    true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"name": schema.StringAttribute{
+							Description: "Parameter name",
+							Required:    true,
+							Validators: []validator.String{
+								stringvalidator.LengthAtLeast(1),
+							},
+						},
+						"description": schema.StringAttribute{
+							Description: "Free-text description of the parameter",
+							Optional:    true,
+							Validators: []validator.String{
+								stringvalidator.LengthAtLeast(1),
+							},
+						},
+						"value_type": schema.StringAttribute{
+							Description: "Parameter type (STRING, NUMBER, LIST, SECRET_TEXT)",
+							Required:    true,
+							Validators: []validator.String{
+								stringvalidator.OneOf("STRING", "NUMBER", "LIST", "SECRET_TEXT"),
+							},
+						},
+						"default_value": schema.StringAttribute{
+							Description: "Value used when an association does not set the parameter. Sensitive, since SECRET_TEXT parameters may have one.",
+							Optional:    true,
+							Sensitive:   true,
+							Validators: []validator.String{
+								stringvalidator.LengthAtLeast(1),
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// Configure adds the provider configured client to the resource
+func (r *CustomDriverResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	c, ok := req.ProviderData.(*client.Client)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *client.Client, got: %T", req.ProviderData),
+		)
+		return
+	}
+
+	r.client = c
+}
+
+// Create creates the resource and sets the initial Terraform state
+func (r *CustomDriverResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan CustomDriverResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	driver, err := r.client.CreateCustomDriver(ctx, customDriverRequest(plan))
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error creating custom driver",
+			"Could not create custom driver: "+apiErrorDetail(err),
+		)
+		return
+	}
+
+	plan.ID = types.StringValue(strconv.Itoa(int(driver.ID)))
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+// Read refreshes the Terraform state with the latest data
+func (r *CustomDriverResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state CustomDriverResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	driverID, err := strconv.ParseInt(state.ID.ValueString(), 10, 32)
+	if err != nil {
+		resp.Diagnostics.AddError("Error parsing custom driver ID", err.Error())
+		return
+	}
+
+	driver, err := r.client.GetCustomDriver(ctx, int32(driverID))
+	if err != nil {
+		var notFound *client.NotFoundError
+		if errors.As(err, &notFound) {
+			resp.State.RemoveResource(ctx)
+			return
+		}
+		resp.Diagnostics.AddError(
+			"Error reading custom driver",
+			"Could not read custom driver: "+apiErrorDetail(err),
+		)
+		return
+	}
+
+	state.Name = types.StringValue(driver.Name)
+	state.Description = stringOrNull(driver.Description)
+	state.Script = types.StringValue(driver.Script)
+	state.MinimalSamplePeriod = types.Int64Value(int64(driver.MinimalSamplePeriod))
+	state.Parameters = nil
+	for _, p := range driver.Parameters {
+		state.Parameters = append(state.Parameters, CustomDriverParameterModel{
+			Name:         types.StringValue(p.Name),
+			Description:  stringOrNull(p.Description),
+			ValueType:    types.StringValue(p.ValueType),
+			DefaultValue: stringOrNull(p.DefaultValue),
+		})
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+// Update updates the resource and sets the updated Terraform state on success
+func (r *CustomDriverResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan CustomDriverResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	driverID, err := strconv.ParseInt(plan.ID.ValueString(), 10, 32)
+	if err != nil {
+		resp.Diagnostics.AddError("Error parsing custom driver ID", err.Error())
+		return
+	}
+
+	// The API replaces the whole driver; existing associations keep running
+	// with the new script
+	_, err = r.client.UpdateCustomDriver(ctx, int32(driverID), customDriverRequest(plan))
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error updating custom driver",
+			"Could not update custom driver: "+apiErrorDetail(err),
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+// Delete deletes the resource and removes the Terraform state on success
+func (r *CustomDriverResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state CustomDriverResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	driverID, err := strconv.ParseInt(state.ID.ValueString(), 10, 32)
+	if err != nil {
+		resp.Diagnostics.AddError("Error parsing custom driver ID", err.Error())
+		return
+	}
+
+	err = r.client.DeleteCustomDriver(ctx, int32(driverID))
+	if err != nil {
+		var notFound *client.NotFoundError
+		if errors.As(err, &notFound) {
+			return
+		}
+		resp.Diagnostics.AddError(
+			"Error deleting custom driver",
+			"Could not delete custom driver: "+apiErrorDetail(err),
+		)
+		return
+	}
+}
+
+// ImportState imports the resource into Terraform state
+func (r *CustomDriverResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+}
+
+func customDriverRequest(plan CustomDriverResourceModel) client.CustomDriverRequest {
+	req := client.CustomDriverRequest{
+		Name:                plan.Name.ValueString(),
+		Description:         plan.Description.ValueString(),
+		Script:              plan.Script.ValueString(),
+		MinimalSamplePeriod: int32(plan.MinimalSamplePeriod.ValueInt64()),
+	}
+	for _, p := range plan.Parameters {
+		req.Parameters = append(req.Parameters, client.CustomDriverParameter{
+			Name:         p.Name.ValueString(),
+			Description:  p.Description.ValueString(),
+			ValueType:    p.ValueType.ValueString(),
+			DefaultValue: p.DefaultValue.ValueString(),
+		})
+	}
+	return req
+}
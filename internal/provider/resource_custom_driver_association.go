@@ -0,0 +1,299 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/domotz/terraform-provider-domotz/internal/client"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/listplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/objectplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var (
+	_ resource.Resource                = &CustomDriverAssociationResource{}
+	_ resource.ResourceWithImportState = &CustomDriverAssociationResource{}
+)
+
+// NewCustomDriverAssociationResource is a helper function to simplify the provider implementation
+func NewCustomDriverAssociationResource() resource.Resource {
+	return &CustomDriverAssociationResource{}
+}
+
+// CustomDriverAssociationResource runs a custom driver against a device
+type CustomDriverAssociationResource struct {
+	client *client.Client
+}
+
+// CustomDriverAssociationResourceModel describes the resource data model
+type CustomDriverAssociationResourceModel struct {
+	ID             types.String                      `tfsdk:"id"`
+	CustomDriverID types.Int64                       `tfsdk:"custom_driver_id"`
+	AgentID        types.Int64                       `tfsdk:"agent_id"`
+	DeviceID       types.Int64                       `tfsdk:"device_id"`
+	SamplePeriod   types.Int64                       `tfsdk:"sample_period"`
+	Credentials    *CustomDriverCredentialsModel     `tfsdk:"credentials"`
+	Parameters     []CustomDriverParameterValueModel `tfsdk:"parameters"`
+}
+
+// CustomDriverCredentialsModel describes the credentials nested object
+type CustomDriverCredentialsModel struct {
+	Username types.String `tfsdk:"username"`
+	Password types.String `tfsdk:"password"`
+}
+
+// CustomDriverParameterValueModel describes one entry of parameters
+type CustomDriverParameterValueModel struct {
+	Name  types.String `tfsdk:"name"`
+	Value types.String `tfsdk:"value"`
+}
+
+// Metadata returns the resource type name
+func (r *CustomDriverAssociationResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_custom_driver_association"
+}
+
+// Schema defines the schema for the resource
+func (r *CustomDriverAssociationResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Runs a custom driver against a device in Domotz. " +
+			"The association cannot be modified in place; any change replaces it.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Description: "Association ID",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"custom_driver_id": schema.Int64Attribute{
+				Description: "ID of the custom driver",
+				Required:    true,
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.RequiresReplace(),
+				},
+			},
+			"agent_id": schema.Int64Attribute{
+				Description: "ID of the collector managing the device",
+				Required:    true,
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.RequiresReplace(),
+				},
+			},
+			"device_id": schema.Int64Attribute{
+				Description: "ID of the device",
+				Required:    true,
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.RequiresReplace(),
+				},
+			},
+			"sample_period": schema.Int64Attribute{
+				Description: "Interval, in seconds, at which the driver runs. Defaults to the driver's minimal sample period.",
+				Optional:    true,
+				Computed:    true,
+				Validators: []validator.Int64{
+					int64validator.OneOf(customDriverSamplePeriods...),
+				},
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+					int64planmodifier.RequiresReplace(),
+				},
+			},
+			"credentials": schema.SingleNestedAttribute{
+				Description: "Device credentials passed to the driver. Domotz never returns them, so changes made outside Terraform are not detected.",
+				Optional:    true,
+				Sensitive:   true,
+				Attributes: map[string]schema.Attribute{
+					"username": schema.StringAttribute{
+						Description: "Username",
+						Required:    true,
+					},
+					"password": schema.StringAttribute{
+						Description: "Password",
+						Required:    true,
+						Sensitive:   true,
+					},
+				},
+				PlanModifiers: []planmodifier.Object{
+					objectplanmodifier.RequiresReplace(),
+				},
+			},
+			"parameters": schema.ListNestedAttribute{
+				Description: "Values for parameters declared by the driver",
+				Optional:    true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"name": schema.StringAttribute{
+							Description: "Parameter name",
+							Required:    true,
+						},
+						"value": schema.StringAttribute{
+							Description: "Parameter value",
+							Required:    true,
+							Sensitive:   true,
+						},
+					},
+				},
+				PlanModifiers: []planmodifier.List{
+					listplanmodifier.RequiresReplace(),
+				},
+			},
+		},
+	}
+}
+
+// Configure adds the provider configured client to the resource
+func (r *CustomDriverAssociationResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	c, ok := req.ProviderData.(*client.Client)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *client.Client, got: %T", req.ProviderData),
+		)
+		return
+	}
+
+	r.client = c
+}
+
+// Create associates the driver with the device
+func (r *CustomDriverAssociationResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan CustomDriverAssociationResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	createReq := client.CreateCustomDriverAssociationRequest{
+		SamplePeriod: int32(plan.SamplePeriod.ValueInt64()),
+	}
+	if plan.Credentials != nil {
+		createReq.Credentials = &client.CustomDriverCredentials{
+			Username: plan.Credentials.Username.ValueString(),
+			Password: plan.Credentials.Password.ValueString(),
+		}
+	}
+	for _, p := range plan.Parameters {
+		createReq.Parameters = append(createReq.Parameters, client.CustomDriverParameterValue{
+			Name:  p.Name.ValueString(),
+			Value: p.Value.ValueString(),
+		})
+	}
+
+	association, err := r.client.CreateCustomDriverAssociation(ctx,
+		int32(plan.CustomDriverID.ValueInt64()),
+		int32(plan.AgentID.ValueInt64()),
+		int32(plan.DeviceID.ValueInt64()),
+		createReq,
+	)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error creating custom driver association",
+			"Could not associate custom driver with device: "+apiErrorDetail(err),
+		)
+		return
+	}
+
+	plan.ID = types.StringValue(strconv.Itoa(int(association.ID)))
+	plan.SamplePeriod = types.Int64Value(int64(association.SamplePeriod))
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+// Read refreshes the Terraform state with the latest data. Credentials are
+// write-only and kept from state.
+func (r *CustomDriverAssociationResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state CustomDriverAssociationResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	associationID, err := strconv.ParseInt(state.ID.ValueString(), 10, 32)
+	if err != nil {
+		resp.Diagnostics.AddError("Error parsing association ID", err.Error())
+		return
+	}
+
+	association, err := r.client.GetCustomDriverAssociation(ctx, int32(associationID))
+	if err != nil {
+		var notFound *client.NotFoundError
+		if errors.As(err, &notFound) {
+			resp.State.RemoveResource(ctx)
+			return
+		}
+		resp.Diagnostics.AddError(
+			"Error reading custom driver association",
+			"Could not read custom driver association: "+apiErrorDetail(err),
+		)
+		return
+	}
+
+	state.CustomDriverID = types.Int64Value(int64(association.CustomDriverID))
+	state.AgentID = types.Int64Value(int64(association.AgentID))
+	state.DeviceID = types.Int64Value(int64(association.DeviceID))
+	state.SamplePeriod = types.Int64Value(int64(association.SamplePeriod))
+	state.Parameters = nil
+	for _, p := range association.Parameters {
+		state.Parameters = append(state.Parameters, CustomDriverParameterValueModel{
+			Name:  types.StringValue(p.Name),
+			Value: types.StringValue(p.Value),
+		})
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+// Update is never called: every argument requires replacement
+func (r *CustomDriverAssociationResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	resp.Diagnostics.AddError(
+		"Update not supported",
+		"Custom driver associations cannot be updated. All changes require replacement.",
+	)
+}
+
+// Delete stops running the driver against the device
+func (r *CustomDriverAssociationResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state CustomDriverAssociationResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	associationID, err := strconv.ParseInt(state.ID.ValueString(), 10, 32)
+	if err != nil {
+		resp.Diagnostics.AddError("Error parsing association ID", err.Error())
+		return
+	}
+
+	err = r.client.DeleteCustomDriverAssociation(ctx, int32(associationID))
+	if err != nil {
+		var notFound *client.NotFoundError
+		if errors.As(err, &notFound) {
+			return
+		}
+		resp.Diagnostics.AddError(
+			"Error deleting custom driver association",
+			"Could not delete custom driver association: "+apiErrorDetail(err),
+		)
+		return
+	}
+}
+
+// ImportState imports the resource into Terraform state
+func (r *CustomDriverAssociationResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+}
@@ -0,0 +1,120 @@
+package provider
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/domotz/terraform-provider-domotz/internal/client"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+)
+
+func TestAccCustomDriverAssociationResource(t *testing.T) {
+	server := testAccMockServer(t)
+	device := server.AddDevice(client.Device{AgentID: testAccAgentID, DisplayName: "ups-01"})
+	driver := server.AddCustomDriver(client.CustomDriver{
+		Name:                "APC UPS",
+		Script:              "function validate() { D.success(); }",
+		MinimalSamplePeriod: 600,
+		Parameters: []client.CustomDriverParameter{
+			{Name: "community", ValueType: "SECRET_TEXT"},
+		},
+	})
+	var associationID, firstID int32
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy: func(s *terraform.State) error {
+			if _, _, ok := server.CustomDriverAssociation(associationID); ok {
+				return fmt.Errorf("custom driver association %d still exists", associationID)
+			}
+			return nil
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCustomDriverAssociationConfig(driver.ID, device.ID, "public"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrSet("domotz_custom_driver_association.test", "id"),
+					// Defaults to the driver's minimal sample period
+					resource.TestCheckResourceAttr("domotz_custom_driver_association.test", "sample_period", "600"),
+					resource.TestCheckResourceAttr("domotz_custom_driver_association.test", "parameters.0.value", "public"),
+					testAccCaptureID("domotz_custom_driver_association.test", "id", &associationID),
+					func(*terraform.State) error {
+						_, creds, ok := server.CustomDriverAssociation(associationID)
+						if !ok || creds == nil || creds.Username != "apc" || creds.Password != "hunter2" {
+							return fmt.Errorf("expected credentials to reach the API, got %+v", creds)
+						}
+						firstID = associationID
+						return nil
+					},
+				),
+			},
+			{
+				// Credentials are write-only
+				ResourceName:            "domotz_custom_driver_association.test",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"credentials"},
+			},
+			{
+				// Changing a parameter value replaces the association
+				Config: testAccCustomDriverAssociationConfig(driver.ID, device.ID, "private"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("domotz_custom_driver_association.test", "parameters.0.value", "private"),
+					testAccCaptureID("domotz_custom_driver_association.test", "id", &associationID),
+					func(*terraform.State) error {
+						if associationID == firstID {
+							return fmt.Errorf("expected association %d to be replaced", firstID)
+						}
+						if _, _, ok := server.CustomDriverAssociation(firstID); ok {
+							return fmt.Errorf("old association %d still exists", firstID)
+						}
+						return nil
+					},
+				),
+			},
+			{
+				PreConfig:          func() { server.DeleteCustomDriverAssociation(associationID) },
+				Config:             testAccCustomDriverAssociationConfig(driver.ID, device.ID, "private"),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+		},
+	})
+}
+
+func TestAccCustomDriverAssociationResource_undeclaredParameter(t *testing.T) {
+	server := testAccMockServer(t)
+	device := server.AddDevice(client.Device{AgentID: testAccAgentID, DisplayName: "ups-01"})
+	driver := server.AddCustomDriver(client.CustomDriver{Name: "No params", Script: "x", MinimalSamplePeriod: 300})
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccCustomDriverAssociationConfig(driver.ID, device.ID, "public"),
+				ExpectError: regexp.MustCompile(`has no parameter "community"`),
+			},
+		},
+	})
+}
+
+func testAccCustomDriverAssociationConfig(driverID, deviceID int32, community string) string {
+	return fmt.Sprintf(`
+resource "domotz_custom_driver_association" "test" {
+  custom_driver_id = %d
+  agent_id         = %d
+  device_id        = %d
+
+  credentials = {
+    username = "apc"
+    password = "hunter2"
+  }
+
+  parameters = [
+    { name = "community", value = %q },
+  ]
+}
+`, driverID, testAccAgentID, deviceID, community)
+}
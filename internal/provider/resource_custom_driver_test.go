@@ -0,0 +1,123 @@
+package provider
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+)
+
+func TestAccCustomDriverResource(t *testing.T) {
+	server := testAccMockServer(t)
+	script := filepath.Join(t.TempDir(), "ups.js")
+	writeScript := func(body string) {
+		if err := os.WriteFile(script, []byte(body), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeScript("function validate() { D.success(); }\n")
+	var driverID int32
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy: func(s *terraform.State) error {
+			if _, ok := server.CustomDriver(driverID); ok {
+				return fmt.Errorf("custom driver %d still exists", driverID)
+			}
+			return nil
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCustomDriverConfig(script, `
+  parameters = [
+    { name = "community", value_type = "SECRET_TEXT" },
+    { name = "oid_prefix", value_type = "STRING", default_value = "1.3.6.1.4.1.318", description = "APC enterprise OID" },
+  ]`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrSet("domotz_custom_driver.test", "id"),
+					resource.TestCheckResourceAttr("domotz_custom_driver.test", "minimal_sample_period", "300"),
+					resource.TestCheckResourceAttr("domotz_custom_driver.test", "parameters.#", "2"),
+					resource.TestCheckResourceAttr("domotz_custom_driver.test", "parameters.1.default_value", "1.3.6.1.4.1.318"),
+					resource.TestCheckNoResourceAttr("domotz_custom_driver.test", "parameters.0.default_value"),
+					testAccCaptureID("domotz_custom_driver.test", "id", &driverID),
+				),
+			},
+			{
+				ResourceName:      "domotz_custom_driver.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			{
+				// A new script revision and dropped parameters update in place
+				PreConfig: func() {
+					writeScript("function validate() { D.success(); }\nfunction get_status() { D.success([]); }\n")
+				},
+				Config: testAccCustomDriverConfig(script, ""),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("domotz_custom_driver.test", "parameters.#", "0"),
+					testAccCaptureID("domotz_custom_driver.test", "id", &driverID),
+					func(*terraform.State) error {
+						d, ok := server.CustomDriver(driverID)
+						if !ok || !strings.Contains(d.Script, "get_status") || len(d.Parameters) != 0 {
+							return fmt.Errorf("unexpected custom driver in API: %+v", d)
+						}
+						return nil
+					},
+				),
+			},
+			{
+				// A script edited in the UI is drift
+				PreConfig: func() {
+					d, _ := server.CustomDriver(driverID)
+					d.Script = "function validate() {}\n"
+					server.UpdateCustomDriver(d)
+				},
+				Config:             testAccCustomDriverConfig(script, ""),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+		},
+	})
+}
+
+func TestAccCustomDriverResource_emptyValues(t *testing.T) {
+	testAccMockServer(t)
+	script := filepath.Join(t.TempDir(), "ups.js")
+	if err := os.WriteFile(script, []byte("function validate() { D.success(); }\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	// Empty strings would read back as null and never converge
+	var steps []resource.TestStep
+	for _, attrs := range []string{
+		`description = ""`,
+		`parameters = [{ name = "", value_type = "STRING" }]`,
+		`parameters = [{ name = "port", value_type = "NUMBER", description = "" }]`,
+		`parameters = [{ name = "port", value_type = "NUMBER", default_value = "" }]`,
+	} {
+		steps = append(steps, resource.TestStep{
+			Config:      testAccCustomDriverConfig(script, attrs),
+			PlanOnly:    true,
+			ExpectError: regexp.MustCompile(`string length must be at least 1`),
+		})
+	}
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps:                    steps,
+	})
+}
+
+func testAccCustomDriverConfig(script, parameters string) string {
+	return fmt.Sprintf(`
+resource "domotz_custom_driver" "test" {
+  name   = "APC UPS"
+  script = file(%q)
+  %s
+}
+`, script, parameters)
+}
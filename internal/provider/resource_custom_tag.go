@@ -100,7 +100,7 @@ func (r *CustomTagResource) Create(ctx context.Context, req resource.CreateReque
 
 	tag, err := r.client.CreateTag(ctx, createReq)
 	if err != nil {
-		resp.Diagnostics.AddError("Error creating tag", err.Error())
+		resp.Diagnostics.AddError("Error creating tag", apiErrorDetail(err))
 		return
 	}
 
@@ -128,7 +128,7 @@ func (r *CustomTagResource) Read(ctx context.Context, req resource.ReadRequest,
 			resp.State.RemoveResource(ctx)
 			return
 		}
-		resp.Diagnostics.AddError("Error reading tag", err.Error())
+		resp.Diagnostics.AddError("Error reading tag", apiErrorDetail(err))
 		return
 	}
 
@@ -160,7 +160,7 @@ func (r *CustomTagResource) Update(ctx context.Context, req resource.UpdateReque
 
 	tag, err := r.client.UpdateTag(ctx, int32(tagID), updateReq)
 	if err != nil {
-		resp.Diagnostics.AddError("Error updating tag", err.Error())
+		resp.Diagnostics.AddError("Error updating tag", apiErrorDetail(err))
 		return
 	}
 
@@ -189,7 +189,7 @@ func (r *CustomTagResource) Delete(ctx context.Context, req resource.DeleteReque
 		if errors.As(err, &notFound) {
 			return
 		}
-		resp.Diagnostics.AddError("Error deleting tag", err.Error())
+		resp.Diagnostics.AddError("Error deleting tag", apiErrorDetail(err))
 		return
 	}
 }
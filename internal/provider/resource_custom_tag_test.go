@@ -0,0 +1,69 @@
+package provider
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+)
+
+func TestAccCustomTagResource(t *testing.T) {
+	server := testAccMockServer(t)
+	var tagID int32
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy: func(s *terraform.State) error {
+			if _, ok := server.Tag(tagID); ok {
+				return fmt.Errorf("tag %d still exists", tagID)
+			}
+			return nil
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCustomTagConfig("Production", "red"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrSet("domotz_custom_tag.test", "id"),
+					resource.TestCheckResourceAttr("domotz_custom_tag.test", "name", "Production"),
+					resource.TestCheckResourceAttr("domotz_custom_tag.test", "colour", "red"),
+					testAccCaptureID("domotz_custom_tag.test", "id", &tagID),
+				),
+			},
+			{
+				ResourceName:      "domotz_custom_tag.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			{
+				Config: testAccCustomTagConfig("Prod", "blue"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("domotz_custom_tag.test", "name", "Prod"),
+					resource.TestCheckResourceAttr("domotz_custom_tag.test", "colour", "blue"),
+					func(*terraform.State) error {
+						tag, ok := server.Tag(tagID)
+						if !ok || tag.Name != "Prod" || tag.Colour != "blue" {
+							return fmt.Errorf("tag not updated in API: %+v", tag)
+						}
+						return nil
+					},
+				),
+			},
+			{
+				PreConfig:          func() { server.DeleteTag(tagID) },
+				Config:             testAccCustomTagConfig("Prod", "blue"),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+		},
+	})
+}
+
+func testAccCustomTagConfig(name, colour string) string {
+	return fmt.Sprintf(`
+resource "domotz_custom_tag" "test" {
+  name   = %q
+  colour = %q
+}
+`, name, colour)
+}
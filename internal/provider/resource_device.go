@@ -13,6 +13,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/listplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
@@ -23,6 +24,7 @@ import (
 var (
 	_ resource.Resource                = &DeviceResource{}
 	_ resource.ResourceWithImportState = &DeviceResource{}
+	_ resource.ResourceWithModifyPlan  = &DeviceResource{}
 )
 
 // NewDeviceResource is a helper function to simplify the provider implementation
@@ -47,10 +49,10 @@ type DeviceResourceModel struct {
 
 // UserDataModel describes the user_data nested object
 type UserDataModel struct {
-	Name   types.String `tfsdk:"name"`
-	Model  types.String `tfsdk:"model"`
-	Vendor types.String `tfsdk:"vendor"`
-	Type   types.String `tfsdk:"type"`
+	Name   types.String    `tfsdk:"name"`
+	Model  types.String    `tfsdk:"model"`
+	Vendor types.String    `tfsdk:"vendor"`
+	Type   DeviceTypeValue `tfsdk:"type"`
 }
 
 // Metadata returns the resource type name
@@ -85,9 +87,12 @@ func (r *DeviceResource) Schema(_ context.Context, _ resource.SchemaRequest, res
 				},
 			},
 			"ip_addresses": schema.ListAttribute{
-				Description: "List of IP addresses for the device",
+				Description: "List of IP addresses for the device. The API cannot change the addresses of an existing device, so changing this forces a new device.",
 				Required:    true,
 				ElementType: types.StringType,
+				PlanModifiers: []planmodifier.List{
+					listplanmodifier.RequiresReplace(),
+				},
 			},
 			"importance": schema.StringAttribute{
 				Description: "Device importance level (VITAL, FLOATING)",
@@ -114,8 +119,10 @@ func (r *DeviceResource) Schema(_ context.Context, _ resource.SchemaRequest, res
 						Optional:    true,
 					},
 					"type": schema.StringAttribute{
-						Description: "Device type",
-						Optional:    true,
+						Description: "Device type, as a label (e.g., \"Router\") or numeric ID from the domotz_device_types data source. " +
+							"State holds the catalogue label; a configured ID or differently cased label naming the same type is kept as written.",
+						Optional:   true,
+						CustomType: DeviceTypeType{},
 					},
 				},
 			},
@@ -167,13 +174,18 @@ func (r *DeviceResource) Create(ctx context.Context, req resource.CreateRequest,
 	}
 
 	if plan.UserData != nil {
-		typeVal, _ := strconv.ParseInt(plan.UserData.Type.ValueString(), 10, 32)
+		typeID, err := deviceTypeID(ctx, r.client, plan.UserData.Type.StringValue)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("user_data").AtName("type"), "Error resolving device type", apiErrorDetail(err))
+			return
+		}
 		createReq.UserData = client.DeviceUserData{
 			Name:   plan.UserData.Name.ValueString(),
 			Model:  plan.UserData.Model.ValueString(),
 			Vendor: plan.UserData.Vendor.ValueString(),
-			Type:   int32(typeVal),
+			Type:   typeID,
 		}
+		plan.UserData.Type = deviceTypeOrNull(deviceTypeLabel(ctx, r.client, typeID))
 	}
 
 	// Create device
@@ -181,7 +193,7 @@ func (r *DeviceResource) Create(ctx context.Context, req resource.CreateRequest,
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error creating device",
-			"Could not create device: "+err.Error(),
+			"Could not create device: "+apiErrorDetail(err),
 		)
 		return
 	}
@@ -222,7 +234,7 @@ func (r *DeviceResource) Read(ctx context.Context, req resource.ReadRequest, res
 		}
 		resp.Diagnostics.AddError(
 			"Error reading device",
-			"Could not read device: "+err.Error(),
+			"Could not read device: "+apiErrorDetail(err),
 		)
 		return
 	}
@@ -239,23 +251,17 @@ func (r *DeviceResource) Read(ctx context.Context, req resource.ReadRequest, res
 	}
 	state.IPAddresses = ipAddressesList
 
-	// Update user data if present
-	if device.UserData.Name != "" || device.UserData.Model != "" || device.UserData.Vendor != "" || device.UserData.Type != 0 {
-		state.UserData = &UserDataModel{
-			Name:   types.StringValue(device.UserData.Name),
-			Model:  types.StringValue(device.UserData.Model),
-			Vendor: types.StringValue(device.UserData.Vendor),
-			Type:   types.StringValue(fmt.Sprintf("%d", device.UserData.Type)),
-		}
-	}
+	// Keep an empty user_data block if the configuration has one
+	state.UserData = readUserData(ctx, r.client, device.UserData, state.UserData != nil)
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
 }
 
 // Update updates the resource and sets the updated Terraform state on success
 func (r *DeviceResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
-	var plan DeviceResourceModel
+	var plan, state DeviceResourceModel
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
@@ -269,26 +275,24 @@ func (r *DeviceResource) Update(ctx context.Context, req resource.UpdateRequest,
 		return
 	}
 
-	// Build update request
+	// Build update request from the changed fields only, so each costs a write
 	updateReq := client.UpdateDeviceRequest{}
 
-	displayName := plan.DisplayName.ValueString()
-	updateReq.DisplayName = &displayName
+	if !plan.DisplayName.Equal(state.DisplayName) {
+		displayName := plan.DisplayName.ValueString()
+		updateReq.DisplayName = &displayName
+	}
 
-	if !plan.Importance.IsNull() {
+	if !plan.Importance.IsNull() && !plan.Importance.Equal(state.Importance) {
 		importance := plan.Importance.ValueString()
 		updateReq.Importance = &importance
 	}
 
-	if plan.UserData != nil {
-		typeVal, _ := strconv.ParseInt(plan.UserData.Type.ValueString(), 10, 32)
-		userData := client.DeviceUserData{
-			Name:   plan.UserData.Name.ValueString(),
-			Model:  plan.UserData.Model.ValueString(),
-			Vendor: plan.UserData.Vendor.ValueString(),
-			Type:   int32(typeVal),
-		}
-		updateReq.UserData = &userData
+	// Send only the user_data fields that changed, so removed fields are cleared
+	updateReq.UserData, err = userDataUpdate(ctx, r.client, plan.UserData, state.UserData)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("user_data").AtName("type"), "Error resolving device type", apiErrorDetail(err))
+		return
 	}
 
 	// Update device
@@ -296,7 +300,7 @@ func (r *DeviceResource) Update(ctx context.Context, req resource.UpdateRequest,
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error updating device",
-			"Could not update device: "+err.Error(),
+			"Could not update device: "+apiErrorDetail(err),
 		)
 		return
 	}
@@ -306,10 +310,22 @@ func (r *DeviceResource) Update(ctx context.Context, req resource.UpdateRequest,
 	if device.Importance != "" {
 		plan.Importance = types.StringValue(device.Importance)
 	}
+	if plan.UserData != nil {
+		plan.UserData.Type = deviceTypeOrNull(deviceTypeLabel(ctx, r.client, device.UserData.Type))
+	}
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
 }
 
+// ModifyPlan validates user_data.type against the device type catalogue, so
+// an unknown type name or ID fails at plan time rather than during apply
+func (r *DeviceResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() || r.client == nil {
+		return
+	}
+	validateUserDataType(ctx, r.client, req.Plan, &resp.Diagnostics)
+}
+
 // Delete deletes the resource and removes the Terraform state on success
 func (r *DeviceResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
 	var state DeviceResourceModel
@@ -336,7 +352,7 @@ func (r *DeviceResource) Delete(ctx context.Context, req resource.DeleteRequest,
 		}
 		resp.Diagnostics.AddError(
 			"Error deleting device",
-			"Could not delete device: "+err.Error(),
+			"Could not delete device: "+apiErrorDetail(err),
 		)
 		return
 	}
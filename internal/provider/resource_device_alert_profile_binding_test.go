@@ -0,0 +1,67 @@
+package provider
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/domotz/terraform-provider-domotz/internal/client"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+)
+
+func TestAccDeviceAlertProfileBindingResource(t *testing.T) {
+	server := testAccMockServer(t)
+	device := server.AddDevice(client.Device{AgentID: testAccAgentID, DisplayName: "core-switch"})
+	var profileID int32
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy: func(s *terraform.State) error {
+			if ids := server.DeviceAlertProfileIDs(testAccAgentID, device.ID); len(ids) != 0 {
+				return fmt.Errorf("device still has alert profiles applied: %v", ids)
+			}
+			return nil
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDeviceAlertProfileBindingConfig(device.ID),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrPair("domotz_device_alert_profile_binding.test", "alert_profile_id", "domotz_alert_profile.test", "id"),
+					testAccCaptureID("domotz_alert_profile.test", "id", &profileID),
+					func(*terraform.State) error {
+						if ids := server.DeviceAlertProfileIDs(testAccAgentID, device.ID); len(ids) != 1 || ids[0] != profileID {
+							return fmt.Errorf("expected alert profile %d applied in API, got %v", profileID, ids)
+						}
+						return nil
+					},
+				),
+			},
+			{
+				ResourceName:      "domotz_device_alert_profile_binding.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			{
+				PreConfig:          func() { server.UnbindDeviceAlertProfile(testAccAgentID, device.ID, profileID) },
+				Config:             testAccDeviceAlertProfileBindingConfig(device.ID),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+		},
+	})
+}
+
+func testAccDeviceAlertProfileBindingConfig(deviceID int32) string {
+	return fmt.Sprintf(`
+resource "domotz_alert_profile" "test" {
+  name   = "Core network"
+  events = ["device_status_down"]
+}
+
+resource "domotz_device_alert_profile_binding" "test" {
+  agent_id         = %d
+  device_id        = %d
+  alert_profile_id = domotz_alert_profile.test.id
+}
+`, testAccAgentID, deviceID)
+}
@@ -107,7 +107,7 @@ func (r *DeviceTagBindingResource) Create(ctx context.Context, req resource.Crea
 
 	err := r.client.BindTagToDevice(ctx, agentID, deviceID, tagID)
 	if err != nil {
-		resp.Diagnostics.AddError("Error binding tag to device", err.Error())
+		resp.Diagnostics.AddError("Error binding tag to device", apiErrorDetail(err))
 		return
 	}
 
@@ -129,7 +129,13 @@ func (r *DeviceTagBindingResource) Read(ctx context.Context, req resource.ReadRe
 	// Verify the binding still exists by listing device tags
 	tags, err := r.client.ListDeviceTags(ctx, agentID, deviceID)
 	if err != nil {
-		resp.Diagnostics.AddError("Error reading device tags", err.Error())
+		var notFound *client.NotFoundError
+		if errors.As(err, &notFound) {
+			// The device is gone, and the binding with it
+			resp.State.RemoveResource(ctx)
+			return
+		}
+		resp.Diagnostics.AddError("Error reading device tags", apiErrorDetail(err))
 		return
 	}
 
@@ -176,7 +182,7 @@ func (r *DeviceTagBindingResource) Delete(ctx context.Context, req resource.Dele
 		if errors.As(err, &notFound) {
 			return
 		}
-		resp.Diagnostics.AddError("Error unbinding tag from device", err.Error())
+		resp.Diagnostics.AddError("Error unbinding tag from device", apiErrorDetail(err))
 		return
 	}
 }
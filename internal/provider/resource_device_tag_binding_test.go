@@ -0,0 +1,68 @@
+package provider
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/domotz/terraform-provider-domotz/internal/client"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+)
+
+func TestAccDeviceTagBindingResource(t *testing.T) {
+	server := testAccMockServer(t)
+	device := server.AddDevice(client.Device{AgentID: testAccAgentID, DisplayName: "core-switch"})
+	var tagID int32
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy: func(s *terraform.State) error {
+			if ids := server.DeviceTagIDs(testAccAgentID, device.ID); len(ids) != 0 {
+				return fmt.Errorf("device still has tags bound: %v", ids)
+			}
+			return nil
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDeviceTagBindingConfig(device.ID),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrPair("domotz_device_tag_binding.test", "tag_id", "domotz_custom_tag.test", "id"),
+					resource.TestCheckResourceAttr("domotz_device_tag_binding.test", "device_id", fmt.Sprint(device.ID)),
+					testAccCaptureID("domotz_custom_tag.test", "id", &tagID),
+					func(*terraform.State) error {
+						if ids := server.DeviceTagIDs(testAccAgentID, device.ID); len(ids) != 1 || ids[0] != tagID {
+							return fmt.Errorf("expected tag %d bound in API, got %v", tagID, ids)
+						}
+						return nil
+					},
+				),
+			},
+			{
+				ResourceName:      "domotz_device_tag_binding.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			{
+				PreConfig:          func() { server.UnbindTag(testAccAgentID, device.ID, tagID) },
+				Config:             testAccDeviceTagBindingConfig(device.ID),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+		},
+	})
+}
+
+func testAccDeviceTagBindingConfig(deviceID int32) string {
+	return fmt.Sprintf(`
+resource "domotz_custom_tag" "test" {
+  name   = "Network Equipment"
+  colour = "blue"
+}
+
+resource "domotz_device_tag_binding" "test" {
+  agent_id  = %d
+  device_id = %d
+  tag_id    = domotz_custom_tag.test.id
+}
+`, testAccAgentID, deviceID)
+}
@@ -0,0 +1,291 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/domotz/terraform-provider-domotz/internal/client"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var (
+	_ resource.Resource                = &DeviceTagsResource{}
+	_ resource.ResourceWithImportState = &DeviceTagsResource{}
+)
+
+// NewDeviceTagsResource is a helper function to simplify the provider implementation
+func NewDeviceTagsResource() resource.Resource {
+	return &DeviceTagsResource{}
+}
+
+// DeviceTagsResource manages the complete set of tags bound to a device
+type DeviceTagsResource struct {
+	client *client.Client
+}
+
+// DeviceTagsResourceModel describes the resource data model
+type DeviceTagsResourceModel struct {
+	ID       types.String `tfsdk:"id"`
+	AgentID  types.Int64  `tfsdk:"agent_id"`
+	DeviceID types.Int64  `tfsdk:"device_id"`
+	TagIDs   types.Set    `tfsdk:"tag_ids"`
+}
+
+// Metadata returns the resource type name
+func (r *DeviceTagsResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_device_tags"
+}
+
+// Schema defines the schema for the resource
+func (r *DeviceTagsResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Authoritatively manages the set of tags bound to a device in Domotz. " +
+			"Tags bound outside Terraform are reported as drift and unbound on the next apply. " +
+			"Do not combine with domotz_device_tag_binding on the same device.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Description: "Resource ID (format: agent_id:device_id)",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"agent_id": schema.Int64Attribute{
+				Description: "ID of the collector managing the device",
+				Required:    true,
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.RequiresReplace(),
+				},
+			},
+			"device_id": schema.Int64Attribute{
+				Description: "ID of the device",
+				Required:    true,
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.RequiresReplace(),
+				},
+			},
+			"tag_ids": schema.SetAttribute{
+				Description: "IDs of all tags that should be bound to the device. An empty set removes every tag.",
+				Required:    true,
+				ElementType: types.Int64Type,
+			},
+		},
+	}
+}
+
+// Configure adds the provider configured client to the resource
+func (r *DeviceTagsResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	c, ok := req.ProviderData.(*client.Client)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *client.Client, got: %T", req.ProviderData),
+		)
+		return
+	}
+
+	r.client = c
+}
+
+// Create binds and unbinds tags until the device carries exactly tag_ids
+func (r *DeviceTagsResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan DeviceTagsResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	agentID := int32(plan.AgentID.ValueInt64())
+	deviceID := int32(plan.DeviceID.ValueInt64())
+
+	var want []int64
+	resp.Diagnostics.Append(plan.TagIDs.ElementsAs(ctx, &want, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := r.converge(ctx, agentID, deviceID, want); err != nil {
+		resp.Diagnostics.AddError("Error setting device tags", apiErrorDetail(err))
+		return
+	}
+
+	plan.ID = types.StringValue(fmt.Sprintf("%d:%d", agentID, deviceID))
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+// Read refreshes tag_ids from the API so bindings made elsewhere show up as drift
+func (r *DeviceTagsResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state DeviceTagsResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	agentID := int32(state.AgentID.ValueInt64())
+	deviceID := int32(state.DeviceID.ValueInt64())
+
+	tags, err := r.client.ListDeviceTags(ctx, agentID, deviceID)
+	if err != nil {
+		var notFound *client.NotFoundError
+		if errors.As(err, &notFound) {
+			resp.State.RemoveResource(ctx)
+			return
+		}
+		resp.Diagnostics.AddError("Error reading device tags", apiErrorDetail(err))
+		return
+	}
+
+	ids := make([]int64, 0, len(tags))
+	for _, tag := range tags {
+		ids = append(ids, int64(tag.ID))
+	}
+	tagIDs, diags := types.SetValueFrom(ctx, types.Int64Type, ids)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	state.TagIDs = tagIDs
+	state.ID = types.StringValue(fmt.Sprintf("%d:%d", agentID, deviceID))
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+// Update converges the device on the new tag_ids
+func (r *DeviceTagsResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan DeviceTagsResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	agentID := int32(plan.AgentID.ValueInt64())
+	deviceID := int32(plan.DeviceID.ValueInt64())
+
+	var want []int64
+	resp.Diagnostics.Append(plan.TagIDs.ElementsAs(ctx, &want, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := r.converge(ctx, agentID, deviceID, want); err != nil {
+		resp.Diagnostics.AddError("Error setting device tags", apiErrorDetail(err))
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+// Delete unbinds every tag the resource manages
+func (r *DeviceTagsResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state DeviceTagsResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	agentID := int32(state.AgentID.ValueInt64())
+	deviceID := int32(state.DeviceID.ValueInt64())
+
+	err := r.converge(ctx, agentID, deviceID, nil)
+	if err != nil {
+		var notFound *client.NotFoundError
+		if errors.As(err, &notFound) {
+			return
+		}
+		resp.Diagnostics.AddError("Error removing device tags", apiErrorDetail(err))
+		return
+	}
+}
+
+// converge lists the tags currently bound to the device, then unbinds the
+// ones not in want and binds the missing ones. Tags are processed in ID
+// order so a partial failure is reproducible.
+func (r *DeviceTagsResource) converge(ctx context.Context, agentID, deviceID int32, want []int64) error {
+	current, err := r.client.ListDeviceTags(ctx, agentID, deviceID)
+	if err != nil {
+		return err
+	}
+
+	wanted := make(map[int32]bool, len(want))
+	for _, id := range want {
+		wanted[int32(id)] = true
+	}
+	bound := make(map[int32]bool, len(current))
+	for _, tag := range current {
+		bound[tag.ID] = true
+	}
+
+	for _, id := range sortedTagIDs(bound) {
+		if wanted[id] {
+			continue
+		}
+		if err := r.client.UnbindTagFromDevice(ctx, agentID, deviceID, id); err != nil {
+			var notFound *client.NotFoundError
+			if errors.As(err, &notFound) {
+				continue
+			}
+			return err
+		}
+	}
+	for _, id := range sortedTagIDs(wanted) {
+		if bound[id] {
+			continue
+		}
+		if err := r.client.BindTagToDevice(ctx, agentID, deviceID, id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func sortedTagIDs(m map[int32]bool) []int32 {
+	ids := make([]int32, 0, len(m))
+	for id := range m {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids
+}
+
+// ImportState imports the resource into Terraform state
+func (r *DeviceTagsResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	// Import format: "agent_id:device_id"
+	parts := strings.Split(req.ID, ":")
+	if len(parts) != 2 {
+		resp.Diagnostics.AddError(
+			"Invalid import ID",
+			"Import ID must be in the format 'agent_id:device_id'",
+		)
+		return
+	}
+
+	agentID, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid agent ID", err.Error())
+		return
+	}
+
+	deviceID, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid device ID", err.Error())
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("agent_id"), agentID)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("device_id"), deviceID)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), req.ID)...)
+}
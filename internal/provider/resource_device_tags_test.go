@@ -0,0 +1,103 @@
+package provider
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/domotz/terraform-provider-domotz/internal/client"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+)
+
+func TestAccDeviceTagsResource(t *testing.T) {
+	server := testAccMockServer(t)
+	device := server.AddDevice(client.Device{AgentID: testAccAgentID, DisplayName: "core-switch"})
+	network := server.AddTag(client.Tag{Name: "Network Equipment", Colour: "blue"})
+	critical := server.AddTag(client.Tag{Name: "Critical", Colour: "red"})
+	manual := server.AddTag(client.Tag{Name: "Manual", Colour: "green"})
+	// Bound before Terraform takes over; the first apply must remove it
+	server.BindTag(testAccAgentID, device.ID, manual.ID)
+
+	expectBound := func(want ...int32) resource.TestCheckFunc {
+		return func(*terraform.State) error {
+			got := server.DeviceTagIDs(testAccAgentID, device.ID)
+			if fmt.Sprint(got) != fmt.Sprint(want) {
+				return fmt.Errorf("expected tags %v bound in API, got %v", want, got)
+			}
+			return nil
+		}
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy: func(s *terraform.State) error {
+			if ids := server.DeviceTagIDs(testAccAgentID, device.ID); len(ids) != 0 {
+				return fmt.Errorf("device still has tags bound: %v", ids)
+			}
+			return nil
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDeviceTagsConfig(device.ID, network.ID),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("domotz_device_tags.test", "id", fmt.Sprintf("%d:%d", testAccAgentID, device.ID)),
+					resource.TestCheckResourceAttr("domotz_device_tags.test", "tag_ids.#", "1"),
+					resource.TestCheckTypeSetElemAttr("domotz_device_tags.test", "tag_ids.*", fmt.Sprint(network.ID)),
+					expectBound(network.ID),
+				),
+			},
+			{
+				ResourceName:      "domotz_device_tags.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			{
+				Config: testAccDeviceTagsConfig(device.ID, network.ID, critical.ID),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("domotz_device_tags.test", "tag_ids.#", "2"),
+					expectBound(network.ID, critical.ID),
+				),
+			},
+			{
+				// A tag bound from the Domotz UI is drift
+				PreConfig:          func() { server.BindTag(testAccAgentID, device.ID, manual.ID) },
+				Config:             testAccDeviceTagsConfig(device.ID, network.ID, critical.ID),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				Config: testAccDeviceTagsConfig(device.ID, network.ID, critical.ID),
+				Check:  expectBound(network.ID, critical.ID),
+			},
+			{
+				// So is a tag unbound outside Terraform
+				PreConfig:          func() { server.UnbindTag(testAccAgentID, device.ID, critical.ID) },
+				Config:             testAccDeviceTagsConfig(device.ID, network.ID, critical.ID),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				Config: testAccDeviceTagsConfig(device.ID),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("domotz_device_tags.test", "tag_ids.#", "0"),
+					expectBound(),
+				),
+			},
+		},
+	})
+}
+
+func testAccDeviceTagsConfig(deviceID int32, tagIDs ...int32) string {
+	ids := make([]string, 0, len(tagIDs))
+	for _, id := range tagIDs {
+		ids = append(ids, fmt.Sprint(id))
+	}
+	return fmt.Sprintf(`
+resource "domotz_device_tags" "test" {
+  agent_id  = %d
+  device_id = %d
+  tag_ids   = [%s]
+}
+`, testAccAgentID, deviceID, strings.Join(ids, ", "))
+}
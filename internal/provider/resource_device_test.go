@@ -0,0 +1,260 @@
+package provider
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"testing"
+
+	"github.com/domotz/terraform-provider-domotz/internal/client"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+)
+
+func TestAccDeviceResource(t *testing.T) {
+	server := testAccMockServer(t)
+	var deviceID int32
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy: func(s *terraform.State) error {
+			if _, ok := server.Device(testAccAgentID, deviceID); ok {
+				return fmt.Errorf("device %d still exists", deviceID)
+			}
+			return nil
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDeviceConfig("External Web Server", "203.0.113.10", "FLOATING", "Dell"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrSet("domotz_device.test", "id"),
+					resource.TestCheckResourceAttr("domotz_device.test", "display_name", "External Web Server"),
+					resource.TestCheckResourceAttr("domotz_device.test", "ip_addresses.#", "1"),
+					resource.TestCheckResourceAttr("domotz_device.test", "ip_addresses.0", "203.0.113.10"),
+					resource.TestCheckResourceAttr("domotz_device.test", "importance", "FLOATING"),
+					resource.TestCheckResourceAttr("domotz_device.test", "user_data.vendor", "Dell"),
+					resource.TestCheckResourceAttr("domotz_device.test", "user_data.type", "12"),
+					testAccCaptureID("domotz_device.test", "id", &deviceID),
+				),
+			},
+			{
+				ResourceName:      "domotz_device.test",
+				ImportState:       true,
+				ImportStateIdFunc: testAccImportStateIDFunc("domotz_device.test", "agent_id", "id"),
+				ImportStateVerify: true,
+				// Imports read the type label back, while the configuration uses the ID
+				ImportStateVerifyIgnore: []string{"user_data.type"},
+			},
+			{
+				Config: testAccDeviceConfig("Public Web Server", "203.0.113.10", "VITAL", "HPE"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("domotz_device.test", "display_name", "Public Web Server"),
+					resource.TestCheckResourceAttr("domotz_device.test", "importance", "VITAL"),
+					resource.TestCheckResourceAttr("domotz_device.test", "user_data.vendor", "HPE"),
+					func(*terraform.State) error {
+						// Renaming must not replace the device
+						device, ok := server.Device(testAccAgentID, deviceID)
+						if !ok || device.DisplayName != "Public Web Server" || device.Importance != "VITAL" || device.UserData.Vendor != "HPE" {
+							return fmt.Errorf("device not updated in API: %+v", device)
+						}
+						return nil
+					},
+				),
+			},
+			{
+				// Unchanged fields are not written again
+				Config: testAccDeviceConfig("Public Web Server", "203.0.113.10", "FLOATING", "HPE"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("domotz_device.test", "importance", "FLOATING"),
+					func(*terraform.State) error {
+						path := fmt.Sprintf("/agent/%d/device/%d/display_name", testAccAgentID, deviceID)
+						if n := server.Requests(http.MethodPut, path); n != 1 {
+							return fmt.Errorf("expected display_name to be written once, got %d writes", n)
+						}
+						return nil
+					},
+				),
+			},
+			{
+				// IP addresses cannot be changed in place, so the device is replaced
+				Config: testAccDeviceConfig("Public Web Server", "203.0.113.20", "VITAL", "HPE"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("domotz_device.test", "ip_addresses.0", "203.0.113.20"),
+					func(s *terraform.State) error {
+						if _, ok := server.Device(testAccAgentID, deviceID); ok {
+							return fmt.Errorf("replaced device %d still exists", deviceID)
+						}
+						return testAccCaptureID("domotz_device.test", "id", &deviceID)(s)
+					},
+					func(*terraform.State) error {
+						device, ok := server.Device(testAccAgentID, deviceID)
+						if !ok || len(device.IPAddresses) != 1 || device.IPAddresses[0] != "203.0.113.20" {
+							return fmt.Errorf("replacement device not found in API: %+v", device)
+						}
+						return nil
+					},
+				),
+			},
+			{
+				PreConfig:          func() { server.DeleteDevice(testAccAgentID, deviceID) },
+				Config:             testAccDeviceConfig("Public Web Server", "203.0.113.20", "VITAL", "HPE"),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+		},
+	})
+}
+
+func testAccDeviceConfig(displayName, ipAddress, importance, vendor string) string {
+	return fmt.Sprintf(`
+resource "domotz_device" "test" {
+  agent_id     = %d
+  display_name = %q
+  ip_addresses = [%q]
+  importance   = %q
+
+  user_data = {
+    name   = "web-01"
+    model  = "PowerEdge R650"
+    vendor = %q
+    type   = "12"
+  }
+}
+`, testAccAgentID, displayName, ipAddress, importance, vendor)
+}
+
+func TestAccDeviceResource_clearUserData(t *testing.T) {
+	server := testAccMockServer(t)
+	var deviceID int32
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDeviceUserDataConfig(`
+  user_data = {
+    name   = "web-01"
+    vendor = "Dell"
+  }`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("domotz_device.test", "user_data.name", "web-01"),
+					resource.TestCheckResourceAttr("domotz_device.test", "user_data.vendor", "Dell"),
+					resource.TestCheckNoResourceAttr("domotz_device.test", "user_data.model"),
+					resource.TestCheckNoResourceAttr("domotz_device.test", "user_data.type"),
+					testAccCaptureID("domotz_device.test", "id", &deviceID),
+				),
+			},
+			{
+				// Removing a field clears it in Domotz
+				Config: testAccDeviceUserDataConfig(`
+  user_data = {
+    name = "web-01"
+  }`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckNoResourceAttr("domotz_device.test", "user_data.vendor"),
+					func(*terraform.State) error {
+						device, _ := server.Device(testAccAgentID, deviceID)
+						if device.UserData != (client.DeviceUserData{Name: "web-01"}) {
+							return fmt.Errorf("expected only user_data.name in API, got %+v", device.UserData)
+						}
+						return nil
+					},
+				),
+			},
+			{
+				// Removing the block clears everything
+				Config: testAccDeviceUserDataConfig(""),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckNoResourceAttr("domotz_device.test", "user_data"),
+					func(*terraform.State) error {
+						device, _ := server.Device(testAccAgentID, deviceID)
+						if device.UserData != (client.DeviceUserData{}) {
+							return fmt.Errorf("expected empty user_data in API, got %+v", device.UserData)
+						}
+						return nil
+					},
+				),
+			},
+		},
+	})
+}
+
+func testAccDeviceUserDataConfig(userData string) string {
+	return fmt.Sprintf(`
+resource "domotz_device" "test" {
+  agent_id     = %d
+  display_name = "External Web Server"
+  ip_addresses = ["203.0.113.10"]
+%s
+}
+`, testAccAgentID, userData)
+}
+
+func TestAccDeviceResource_userDataType(t *testing.T) {
+	server := testAccMockServer(t)
+	var deviceID int32
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				// Names are matched case-insensitively
+				Config: testAccDeviceUserDataConfig(`
+  user_data = {
+    type = "router"
+  }`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("domotz_device.test", "user_data.type", "router"),
+					testAccCaptureID("domotz_device.test", "id", &deviceID),
+					func(*terraform.State) error {
+						if device, _ := server.Device(testAccAgentID, deviceID); device.UserData.Type != 7 {
+							return fmt.Errorf("expected type 7 in API, got %d", device.UserData.Type)
+						}
+						return nil
+					},
+				),
+			},
+			{
+				ResourceName:      "domotz_device.test",
+				ImportState:       true,
+				ImportStateIdFunc: testAccImportStateIDFunc("domotz_device.test", "agent_id", "id"),
+				ImportStateCheck: func(states []*terraform.InstanceState) error {
+					if got := states[0].Attributes["user_data.type"]; got != "Router" {
+						return fmt.Errorf("expected imported type Router, got %q", got)
+					}
+					return nil
+				},
+			},
+			{
+				// Switching to the numeric ID of the same type is a no-op in Domotz
+				Config: testAccDeviceUserDataConfig(`
+  user_data = {
+    type = "7"
+  }`),
+				Check: resource.TestCheckResourceAttr("domotz_device.test", "user_data.type", "7"),
+			},
+			{
+				Config: testAccDeviceUserDataConfig(`
+  user_data = {
+    type = "15"
+  }`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("domotz_device.test", "user_data.type", "15"),
+					func(*terraform.State) error {
+						if device, _ := server.Device(testAccAgentID, deviceID); device.UserData.Type != 15 {
+							return fmt.Errorf("expected type 15 in API, got %d", device.UserData.Type)
+						}
+						return nil
+					},
+				),
+			},
+			{
+				Config: testAccDeviceUserDataConfig(`
+  user_data = {
+    type = "Toaster"
+  }`),
+				ExpectError: regexp.MustCompile(`"Toaster" is not a known device type`),
+			},
+		},
+	})
+}
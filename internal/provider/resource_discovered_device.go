@@ -0,0 +1,605 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/domotz/terraform-provider-domotz/internal/client"
+	"github.com/hashicorp/terraform-plugin-framework-validators/resourcevalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure the implementation satisfies the expected interfaces
+var (
+	_ resource.Resource                     = &DiscoveredDeviceResource{}
+	_ resource.ResourceWithImportState      = &DiscoveredDeviceResource{}
+	_ resource.ResourceWithModifyPlan       = &DiscoveredDeviceResource{}
+	_ resource.ResourceWithConfigValidators = &DiscoveredDeviceResource{}
+)
+
+// originalFieldsKey is the private state key holding the device fields as
+// they were before adoption, restored on destroy when reset_on_destroy is set
+const originalFieldsKey = "original_fields"
+
+var hwAddressRegexp = regexp.MustCompile(`^([0-9A-Fa-f]{2}[:-]){5}[0-9A-Fa-f]{2}$`)
+
+// NewDiscoveredDeviceResource is a helper function to simplify the provider implementation
+func NewDiscoveredDeviceResource() resource.Resource {
+	return &DiscoveredDeviceResource{}
+}
+
+// DiscoveredDeviceResource defines the resource implementation
+type DiscoveredDeviceResource struct {
+	client *client.Client
+}
+
+// DiscoveredDeviceResourceModel describes the resource data model
+type DiscoveredDeviceResourceModel struct {
+	ID             types.String   `tfsdk:"id"`
+	AgentID        types.Int64    `tfsdk:"agent_id"`
+	DeviceID       types.Int64    `tfsdk:"device_id"`
+	HWAddress      types.String   `tfsdk:"hw_address"`
+	IPAddress      types.String   `tfsdk:"ip_address"`
+	DisplayName    types.String   `tfsdk:"display_name"`
+	Importance     types.String   `tfsdk:"importance"`
+	Zone           types.String   `tfsdk:"zone"`
+	UserData       *UserDataModel `tfsdk:"user_data"`
+	ResetOnDestroy types.Bool     `tfsdk:"reset_on_destroy"`
+}
+
+// discoveredDeviceFields are the managed fields recorded at adoption
+type discoveredDeviceFields struct {
+	DisplayName string                `json:"display_name"`
+	Importance  string                `json:"importance"`
+	Zone        string                `json:"zone"`
+	UserData    client.DeviceUserData `json:"user_data"`
+	// Managed names the attributes ever configured; only those are restored
+	Managed []string `json:"managed"`
+}
+
+// manage adds the attributes set in config to Managed and reports whether
+// any were new
+func (f *discoveredDeviceFields) manage(config DiscoveredDeviceResourceModel) bool {
+	configured := map[string]bool{
+		"display_name": !config.DisplayName.IsNull(),
+		"importance":   !config.Importance.IsNull(),
+		"zone":         !config.Zone.IsNull(),
+		"user_data":    config.UserData != nil,
+	}
+	changed := false
+	for _, name := range []string{"display_name", "importance", "zone", "user_data"} {
+		if configured[name] && !f.manages(name) {
+			f.Managed = append(f.Managed, name)
+			changed = true
+		}
+	}
+	return changed
+}
+
+// manages reports whether the attribute was ever configured
+func (f discoveredDeviceFields) manages(name string) bool {
+	for _, m := range f.Managed {
+		if m == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Metadata returns the resource type name
+func (r *DiscoveredDeviceResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_discovered_device"
+}
+
+// Schema defines the schema for the resource
+func (r *DiscoveredDeviceResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Manages the metadata of a device discovered by a collector. The device is looked up by ID, MAC address or IP address " +
+			"and is never created or deleted by Terraform. Arguments that are not set are left as they are in Domotz.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Description: "Device ID",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"agent_id": schema.Int64Attribute{
+				Description: "ID of the agent (collector) that discovered the device",
+				Required:    true,
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.RequiresReplace(),
+				},
+			},
+			"device_id": schema.Int64Attribute{
+				Description: "ID of the device to adopt. Exactly one of device_id, hw_address or ip_address must be set.",
+				Optional:    true,
+				Computed:    true,
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+					int64planmodifier.RequiresReplace(),
+				},
+			},
+			"hw_address": schema.StringAttribute{
+				Description: "MAC address of the device to adopt (e.g., 00:11:22:33:44:55)",
+				Optional:    true,
+				Computed:    true,
+				Validators: []validator.String{
+					stringvalidator.RegexMatches(hwAddressRegexp, "must be a MAC address such as 00:11:22:33:44:55"),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"ip_address": schema.StringAttribute{
+				Description: "IP address of the device to adopt. Must match exactly one device.",
+				Optional:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"display_name": schema.StringAttribute{
+				Description: "Display name for the device",
+				Optional:    true,
+				Computed:    true,
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"importance": schema.StringAttribute{
+				Description: "Device importance level (VITAL, FLOATING)",
+				Optional:    true,
+				Computed:    true,
+				Validators: []validator.String{
+					stringvalidator.OneOf("VITAL", "FLOATING"),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"zone": schema.StringAttribute{
+				Description: "Zone the device belongs to",
+				Optional:    true,
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"user_data": schema.SingleNestedAttribute{
+				Description: "Custom metadata for the device. Fields removed from the block are cleared in Domotz; removing the whole block stops managing it.",
+				Optional:    true,
+				Attributes: map[string]schema.Attribute{
+					"name": schema.StringAttribute{
+						Description: "Custom name",
+						Optional:    true,
+					},
+					"model": schema.StringAttribute{
+						Description: "Device model",
+						Optional:    true,
+					},
+					"vendor": schema.StringAttribute{
+						Description: "Device vendor",
+						Optional:    true,
+					},
+					"type": schema.StringAttribute{
+						Description: "Device type, as a label (e.g., \"Router\") or numeric ID from the domotz_device_types data source. " +
+							"State holds the catalogue label; a configured ID or differently cased label naming the same type is kept as written.",
+						Optional:   true,
+						CustomType: DeviceTypeType{},
+					},
+				},
+			},
+			"reset_on_destroy": schema.BoolAttribute{
+				Description: "Restore display_name, importance, zone and user_data to their values before adoption when the resource is destroyed. " +
+					"Only the arguments that were ever configured are restored, and the device itself is never deleted. Defaults to false.",
+				Optional: true,
+				Computed: true,
+				Default:  booldefault.StaticBool(false),
+			},
+		},
+	}
+}
+
+// ConfigValidators requires exactly one way of finding the device
+func (r *DiscoveredDeviceResource) ConfigValidators(_ context.Context) []resource.ConfigValidator {
+	return []resource.ConfigValidator{
+		resourcevalidator.ExactlyOneOf(
+			path.MatchRoot("device_id"),
+			path.MatchRoot("hw_address"),
+			path.MatchRoot("ip_address"),
+		),
+	}
+}
+
+// Configure adds the provider configured client to the resource
+func (r *DiscoveredDeviceResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	c, ok := req.ProviderData.(*client.Client)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *client.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	r.client = c
+}
+
+// ModifyPlan validates user_data.type against the device type catalogue
+func (r *DiscoveredDeviceResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() || r.client == nil {
+		return
+	}
+	validateUserDataType(ctx, r.client, req.Plan, &resp.Diagnostics)
+	if !req.State.Raw.IsNull() {
+		r.keepSelectedDevice(ctx, req, resp)
+	}
+}
+
+// keepSelectedDevice drops the replacement planned for a changed hw_address
+// or ip_address when the new value still selects the managed device, e.g.
+// after an import, which only records the device ID
+func (r *DiscoveredDeviceResource) keepSelectedDevice(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	selectors := path.Paths{path.Root("hw_address"), path.Root("ip_address")}
+	for _, p := range resp.RequiresReplace {
+		if !selectors.Contains(p) {
+			return
+		}
+	}
+	if len(resp.RequiresReplace) == 0 {
+		return
+	}
+
+	var config, state DiscoveredDeviceResourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() || config.HWAddress.IsUnknown() || config.IPAddress.IsUnknown() || config.AgentID.IsUnknown() {
+		return
+	}
+
+	device, err := r.findDevice(ctx, int32(config.AgentID.ValueInt64()), config)
+	if err != nil || strconv.Itoa(int(device.ID)) != state.ID.ValueString() {
+		// Replace, and report any lookup error from Create
+		return
+	}
+	resp.RequiresReplace = nil
+}
+
+// Create adopts the discovered device and applies the configured fields
+func (r *DiscoveredDeviceResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan DiscoveredDeviceResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	agentID := int32(plan.AgentID.ValueInt64())
+	device, err := r.findDevice(ctx, agentID, plan)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error finding discovered device",
+			"Could not find device: "+apiErrorDetail(err),
+		)
+		return
+	}
+
+	// Record the fields as found, so reset_on_destroy can restore the ones
+	// this resource manages
+	var config DiscoveredDeviceResourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	fields := discoveredDeviceFields{
+		DisplayName: device.DisplayName,
+		Importance:  device.Importance,
+		Zone:        device.Details.Zone,
+		UserData:    device.UserData,
+	}
+	fields.manage(config)
+	original, err := json.Marshal(fields)
+	if err != nil {
+		resp.Diagnostics.AddError("Error recording device fields", err.Error())
+		return
+	}
+	resp.Diagnostics.Append(resp.Private.SetKey(ctx, originalFieldsKey, original)...)
+
+	// Compare the plan against the device as found
+	current := &DiscoveredDeviceResourceModel{
+		DisplayName: types.StringValue(device.DisplayName),
+		Importance:  types.StringValue(device.Importance),
+		Zone:        types.StringValue(device.Details.Zone),
+		UserData:    readUserData(ctx, r.client, device.UserData, true),
+	}
+	device, err = r.apply(ctx, agentID, device.ID, plan, current)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error updating discovered device",
+			"Could not update device: "+apiErrorDetail(err),
+		)
+		return
+	}
+
+	r.setState(ctx, &plan, device)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+// Read refreshes the Terraform state with the latest data
+func (r *DiscoveredDeviceResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state DiscoveredDeviceResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	deviceID, err := strconv.ParseInt(state.ID.ValueString(), 10, 32)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error parsing device ID",
+			"Could not parse device ID: "+err.Error(),
+		)
+		return
+	}
+
+	device, err := r.client.GetDevice(ctx, int32(state.AgentID.ValueInt64()), int32(deviceID))
+	if err != nil {
+		var notFound *client.NotFoundError
+		if errors.As(err, &notFound) {
+			resp.State.RemoveResource(ctx)
+			return
+		}
+		resp.Diagnostics.AddError(
+			"Error reading discovered device",
+			"Could not read device: "+apiErrorDetail(err),
+		)
+		return
+	}
+
+	r.setState(ctx, &state, device)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+// Update applies changed fields to the device
+func (r *DiscoveredDeviceResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan, state, config DiscoveredDeviceResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Fields configured for the first time are restored on destroy too
+	data, diags := req.Private.GetKey(ctx, originalFieldsKey)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if data != nil {
+		var fields discoveredDeviceFields
+		if err := json.Unmarshal(data, &fields); err != nil {
+			resp.Diagnostics.AddError("Error reading recorded device fields", err.Error())
+			return
+		}
+		if fields.manage(config) {
+			data, err := json.Marshal(fields)
+			if err != nil {
+				resp.Diagnostics.AddError("Error recording device fields", err.Error())
+				return
+			}
+			resp.Diagnostics.Append(resp.Private.SetKey(ctx, originalFieldsKey, data)...)
+		}
+	}
+
+	deviceID, err := strconv.ParseInt(state.ID.ValueString(), 10, 32)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error parsing device ID",
+			"Could not parse device ID: "+err.Error(),
+		)
+		return
+	}
+
+	device, err := r.apply(ctx, int32(plan.AgentID.ValueInt64()), int32(deviceID), plan, &state)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error updating discovered device",
+			"Could not update device: "+apiErrorDetail(err),
+		)
+		return
+	}
+
+	r.setState(ctx, &plan, device)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+// Delete releases the device from management. The device is never deleted;
+// with reset_on_destroy the fields it managed are restored to their values
+// before adoption.
+func (r *DiscoveredDeviceResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state DiscoveredDeviceResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() || !state.ResetOnDestroy.ValueBool() {
+		return
+	}
+
+	data, diags := req.Private.GetKey(ctx, originalFieldsKey)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if data == nil {
+		resp.Diagnostics.AddWarning(
+			"Device fields not reset",
+			"No values from before adoption were recorded for this device, e.g. because it was imported. Its fields were left unchanged.",
+		)
+		return
+	}
+
+	var original discoveredDeviceFields
+	if err := json.Unmarshal(data, &original); err != nil {
+		resp.Diagnostics.AddError("Error reading recorded device fields", err.Error())
+		return
+	}
+
+	deviceID, err := strconv.ParseInt(state.ID.ValueString(), 10, 32)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error parsing device ID",
+			"Could not parse device ID: "+err.Error(),
+		)
+		return
+	}
+
+	var updateReq client.UpdateDeviceRequest
+	if original.manages("display_name") {
+		updateReq.DisplayName = &original.DisplayName
+	}
+	if original.manages("importance") && original.Importance != "" {
+		updateReq.Importance = &original.Importance
+	}
+	if original.manages("zone") {
+		updateReq.Zone = &original.Zone
+	}
+	if original.manages("user_data") {
+		updateReq.UserData = &client.UpdateDeviceUserData{
+			Name:   &original.UserData.Name,
+			Model:  &original.UserData.Model,
+			Vendor: &original.UserData.Vendor,
+			Type:   &original.UserData.Type,
+		}
+	}
+	if updateReq == (client.UpdateDeviceRequest{}) {
+		return
+	}
+
+	_, err = r.client.UpdateDevice(ctx, int32(state.AgentID.ValueInt64()), int32(deviceID), updateReq)
+	if err != nil {
+		var notFound *client.NotFoundError
+		if errors.As(err, &notFound) {
+			return
+		}
+		resp.Diagnostics.AddError(
+			"Error resetting discovered device",
+			"Could not reset device: "+apiErrorDetail(err),
+		)
+	}
+}
+
+// ImportState imports the resource into Terraform state
+func (r *DiscoveredDeviceResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	// Import format: "agent_id:device_id"
+	parts := strings.Split(req.ID, ":")
+	if len(parts) != 2 {
+		resp.Diagnostics.AddError(
+			"Invalid import ID",
+			"Import ID must be in the format 'agent_id:device_id'",
+		)
+		return
+	}
+
+	agentID, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Invalid agent ID",
+			"Could not parse agent ID: "+err.Error(),
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("agent_id"), agentID)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), parts[1])...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("reset_on_destroy"), false)...)
+}
+
+// findDevice looks up the device by whichever of device_id, hw_address or ip_address is set
+func (r *DiscoveredDeviceResource) findDevice(ctx context.Context, agentID int32, plan DiscoveredDeviceResourceModel) (*client.Device, error) {
+	if !plan.DeviceID.IsNull() && !plan.DeviceID.IsUnknown() {
+		return r.client.GetDevice(ctx, agentID, int32(plan.DeviceID.ValueInt64()))
+	}
+
+	selector := deviceSelector{
+		hwAddress: plan.HWAddress.ValueString(),
+		ipAddress: plan.IPAddress.ValueString(),
+	}
+	matches, err := selector.find(ctx, r.client, []int32{agentID})
+	if err != nil {
+		return nil, err
+	}
+	device, err := selector.only(matches, fmt.Sprintf("on agent %d", agentID))
+	var ambiguous *ambiguousDeviceError
+	if errors.As(err, &ambiguous) {
+		return nil, fmt.Errorf("%w\nUse device_id instead.", err)
+	}
+	return device, err
+}
+
+// apply sends the fields of plan that differ from current and returns the updated device
+func (r *DiscoveredDeviceResource) apply(ctx context.Context, agentID, deviceID int32, plan DiscoveredDeviceResourceModel, current *DiscoveredDeviceResourceModel) (*client.Device, error) {
+	var updateReq client.UpdateDeviceRequest
+	if stringChanged(plan.DisplayName, current.DisplayName) {
+		v := plan.DisplayName.ValueString()
+		updateReq.DisplayName = &v
+	}
+	if stringChanged(plan.Importance, current.Importance) {
+		v := plan.Importance.ValueString()
+		updateReq.Importance = &v
+	}
+	if stringChanged(plan.Zone, current.Zone) {
+		v := plan.Zone.ValueString()
+		updateReq.Zone = &v
+	}
+	// user_data is only managed while the block is configured
+	if plan.UserData != nil {
+		userData, err := userDataUpdate(ctx, r.client, plan.UserData, current.UserData)
+		if err != nil {
+			return nil, err
+		}
+		updateReq.UserData = userData
+	}
+	return r.client.UpdateDevice(ctx, agentID, deviceID, updateReq)
+}
+
+// setState copies the device into the model. user_data is only tracked while
+// configured, so removing the block releases it rather than clearing it.
+func (r *DiscoveredDeviceResource) setState(ctx context.Context, m *DiscoveredDeviceResourceModel, device *client.Device) {
+	m.ID = types.StringValue(strconv.Itoa(int(device.ID)))
+	m.DeviceID = types.Int64Value(int64(device.ID))
+	// Keep the configured spelling of the MAC address while it matches
+	if m.HWAddress.IsNull() || m.HWAddress.IsUnknown() || normalizeHWAddress(m.HWAddress.ValueString()) != normalizeHWAddress(device.HWAddress) {
+		m.HWAddress = types.StringValue(device.HWAddress)
+	}
+	m.DisplayName = types.StringValue(device.DisplayName)
+	m.Importance = types.StringValue(device.Importance)
+	m.Zone = types.StringValue(device.Details.Zone)
+	if m.UserData != nil {
+		m.UserData = readUserData(ctx, r.client, device.UserData, true)
+	}
+}
+
+// stringChanged reports whether a configured value differs from the current one
+func stringChanged(planned, current types.String) bool {
+	return !planned.IsNull() && !planned.IsUnknown() && planned.ValueString() != current.ValueString()
+}
@@ -0,0 +1,281 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/domotz/terraform-provider-domotz/internal/client"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+)
+
+func TestAccDiscoveredDeviceResource(t *testing.T) {
+	server := testAccMockServer(t)
+	original := server.AddDevice(client.Device{
+		AgentID:     testAccAgentID,
+		DisplayName: "ubnt-usw-24",
+		Protocol:    "IP",
+		IPAddresses: []string{"192.168.1.2"},
+		HWAddress:   "00:11:22:AA:BB:CC",
+		Importance:  "FLOATING",
+		Details:     client.DeviceDetails{Zone: "Default"},
+		UserData:    client.DeviceUserData{Vendor: "Ubiquiti"},
+	})
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy: func(s *terraform.State) error {
+			device, ok := server.Device(testAccAgentID, original.ID)
+			if !ok {
+				return fmt.Errorf("discovered device %d was deleted", original.ID)
+			}
+			if device.DisplayName != original.DisplayName || device.Importance != original.Importance ||
+				device.Details.Zone != original.Details.Zone || device.UserData != original.UserData {
+				return fmt.Errorf("device not reset on destroy: %+v", device)
+			}
+			return nil
+		},
+		Steps: []resource.TestStep{
+			{
+				// MAC addresses match regardless of case and separators
+				Config: testAccDiscoveredDeviceConfig(`hw_address = "00-11-22-aa-bb-cc"`, "Core Switch", "Rack 1"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("domotz_discovered_device.test", "id", fmt.Sprint(original.ID)),
+					resource.TestCheckResourceAttr("domotz_discovered_device.test", "device_id", fmt.Sprint(original.ID)),
+					resource.TestCheckResourceAttr("domotz_discovered_device.test", "hw_address", "00-11-22-aa-bb-cc"),
+					resource.TestCheckResourceAttr("domotz_discovered_device.test", "display_name", "Core Switch"),
+					resource.TestCheckResourceAttr("domotz_discovered_device.test", "importance", "VITAL"),
+					resource.TestCheckResourceAttr("domotz_discovered_device.test", "zone", "Rack 1"),
+					resource.TestCheckResourceAttr("domotz_discovered_device.test", "user_data.type", "Switch"),
+					func(*terraform.State) error {
+						device, _ := server.Device(testAccAgentID, original.ID)
+						if device.DisplayName != "Core Switch" || device.Importance != "VITAL" || device.Details.Zone != "Rack 1" ||
+							device.UserData != (client.DeviceUserData{Type: 15}) {
+							return fmt.Errorf("device not updated in API: %+v", device)
+						}
+						return nil
+					},
+				),
+			},
+			{
+				ResourceName:            "domotz_discovered_device.test",
+				ImportState:             true,
+				ImportStateIdFunc:       testAccImportStateIDFunc("domotz_discovered_device.test", "agent_id", "id"),
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"hw_address", "user_data", "reset_on_destroy"},
+			},
+			{
+				Config: testAccDiscoveredDeviceConfig(`hw_address = "00-11-22-aa-bb-cc"`, "Core Switch 1", "Rack 2"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("domotz_discovered_device.test", "id", fmt.Sprint(original.ID)),
+					func(*terraform.State) error {
+						device, _ := server.Device(testAccAgentID, original.ID)
+						if device.DisplayName != "Core Switch 1" || device.Details.Zone != "Rack 2" {
+							return fmt.Errorf("device not updated in API: %+v", device)
+						}
+						return nil
+					},
+				),
+			},
+		},
+	})
+}
+
+func TestAccDiscoveredDeviceResource_byIPAddress(t *testing.T) {
+	server := testAccMockServer(t)
+	device := server.AddDevice(client.Device{
+		AgentID:     testAccAgentID,
+		DisplayName: "printer",
+		IPAddresses: []string{"192.168.1.50"},
+		Importance:  "FLOATING",
+	})
+	for i := 0; i < 2; i++ {
+		server.AddDevice(client.Device{AgentID: testAccAgentID, DisplayName: "dhcp-client", IPAddresses: []string{"192.168.1.99"}})
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy: func(s *terraform.State) error {
+			// Without reset_on_destroy the device keeps the managed values
+			if d, ok := server.Device(testAccAgentID, device.ID); !ok || d.DisplayName != "Office Printer" {
+				return fmt.Errorf("expected device to be kept as managed, got %+v", d)
+			}
+			return nil
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+resource "domotz_discovered_device" "test" {
+  agent_id   = %d
+  ip_address = "192.168.1.99"
+}
+`, testAccAgentID),
+				ExpectError: regexp.MustCompile(`2 devices with IP address 192.168.1.99`),
+			},
+			{
+				Config: fmt.Sprintf(`
+resource "domotz_discovered_device" "test" {
+  agent_id     = %d
+  ip_address   = "192.168.1.50"
+  display_name = "Office Printer"
+}
+`, testAccAgentID),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("domotz_discovered_device.test", "device_id", fmt.Sprint(device.ID)),
+					resource.TestCheckResourceAttr("domotz_discovered_device.test", "importance", "FLOATING"),
+					resource.TestCheckNoResourceAttr("domotz_discovered_device.test", "user_data"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccDiscoveredDeviceResource_importBySelector(t *testing.T) {
+	server := testAccMockServer(t)
+	device := server.AddDevice(client.Device{
+		AgentID:     testAccAgentID,
+		DisplayName: "nas",
+		IPAddresses: []string{"192.168.1.60"},
+		HWAddress:   "00:11:22:DD:EE:FF",
+		Importance:  "FLOATING",
+	})
+	config := func(selector string) string {
+		return fmt.Sprintf(`
+resource "domotz_discovered_device" "test" {
+  agent_id     = %d
+  %s
+  display_name = "Backup NAS"
+}
+`, testAccAgentID, selector)
+	}
+	inPlace := resource.ConfigPlanChecks{
+		PreApply: []plancheck.PlanCheck{
+			plancheck.ExpectResourceAction("domotz_discovered_device.test", plancheck.ResourceActionUpdate),
+		},
+	}
+
+	// Import only records the device ID; a selector naming the same device
+	// updates it in place rather than replacing it
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:             config(`ip_address = "192.168.1.60"`),
+				ResourceName:       "domotz_discovered_device.test",
+				ImportState:        true,
+				ImportStateId:      fmt.Sprintf("%d:%d", testAccAgentID, device.ID),
+				ImportStatePersist: true,
+			},
+			{
+				Config:           config(`ip_address = "192.168.1.60"`),
+				ConfigPlanChecks: inPlace,
+				Check:            resource.TestCheckResourceAttr("domotz_discovered_device.test", "display_name", "Backup NAS"),
+			},
+			{
+				Config:           config(`hw_address = "00-11-22-dd-ee-ff"`),
+				ConfigPlanChecks: inPlace,
+				Check:            resource.TestCheckResourceAttr("domotz_discovered_device.test", "id", fmt.Sprint(device.ID)),
+			},
+		},
+	})
+}
+
+func TestAccDiscoveredDeviceResource_resetManagedFields(t *testing.T) {
+	server := testAccMockServer(t)
+	original := server.AddDevice(client.Device{
+		AgentID:     testAccAgentID,
+		DisplayName: "camera",
+		IPAddresses: []string{"192.168.1.70"},
+		Importance:  "FLOATING",
+		UserData:    client.DeviceUserData{Vendor: "Axis"},
+	})
+	config := fmt.Sprintf(`
+resource "domotz_discovered_device" "test" {
+  agent_id         = %d
+  device_id        = %d
+  display_name     = "Lobby Camera"
+  reset_on_destroy = true
+}
+`, testAccAgentID, original.ID)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy: func(s *terraform.State) error {
+			// Only display_name was managed; the edits made in Domotz are kept
+			device, _ := server.Device(testAccAgentID, original.ID)
+			if device.DisplayName != "camera" || device.Details.Zone != "Lobby" || device.UserData.Vendor != "Axis Communications" {
+				return fmt.Errorf("unexpected device after destroy: %+v", device)
+			}
+			return nil
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check:  resource.TestCheckResourceAttr("domotz_discovered_device.test", "display_name", "Lobby Camera"),
+			},
+			{
+				PreConfig: func() {
+					vendor, zone := "Axis Communications", "Lobby"
+					_, err := server.Client().UpdateDevice(context.Background(), testAccAgentID, original.ID, client.UpdateDeviceRequest{
+						Zone:     &zone,
+						UserData: &client.UpdateDeviceUserData{Vendor: &vendor},
+					})
+					if err != nil {
+						t.Fatal(err)
+					}
+				},
+				Config: config,
+				Check:  resource.TestCheckResourceAttr("domotz_discovered_device.test", "zone", "Lobby"),
+			},
+		},
+	})
+}
+
+func TestAccDiscoveredDeviceResource_validation(t *testing.T) {
+	testAccMockServer(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+resource "domotz_discovered_device" "test" {
+  agent_id   = %d
+  device_id  = 1
+  ip_address = "192.168.1.50"
+}
+`, testAccAgentID),
+				ExpectError: regexp.MustCompile(`Invalid Attribute Combination`),
+			},
+			{
+				Config: fmt.Sprintf(`
+resource "domotz_discovered_device" "test" {
+  agent_id   = %d
+  hw_address = "not-a-mac"
+}
+`, testAccAgentID),
+				ExpectError: regexp.MustCompile(`must be a MAC address`),
+			},
+		},
+	})
+}
+
+func testAccDiscoveredDeviceConfig(selector, displayName, zone string) string {
+	return fmt.Sprintf(`
+resource "domotz_discovered_device" "test" {
+  agent_id         = %d
+  %s
+  display_name     = %q
+  importance       = "VITAL"
+  zone             = %q
+  reset_on_destroy = true
+
+  user_data = {
+    type = "Switch"
+  }
+}
+`, testAccAgentID, selector, displayName, zone)
+}
@@ -0,0 +1,387 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/domotz/terraform-provider-domotz/internal/client"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var (
+	_ resource.Resource                   = &SNMPCredentialsResource{}
+	_ resource.ResourceWithImportState    = &SNMPCredentialsResource{}
+	_ resource.ResourceWithValidateConfig = &SNMPCredentialsResource{}
+)
+
+// NewSNMPCredentialsResource is a helper function to simplify the provider implementation
+func NewSNMPCredentialsResource() resource.Resource {
+	return &SNMPCredentialsResource{}
+}
+
+// SNMPCredentialsResource manages the SNMP authentication of a device
+type SNMPCredentialsResource struct {
+	client *client.Client
+}
+
+// SNMPCredentialsResourceModel describes the resource data model
+type SNMPCredentialsResourceModel struct {
+	ID                   types.String `tfsdk:"id"`
+	AgentID              types.Int64  `tfsdk:"agent_id"`
+	DeviceID             types.Int64  `tfsdk:"device_id"`
+	Version              types.String `tfsdk:"version"`
+	Community            types.String `tfsdk:"community"`
+	Username             types.String `tfsdk:"username"`
+	AuthProtocol         types.String `tfsdk:"auth_protocol"`
+	AuthKey              types.String `tfsdk:"auth_key"`
+	PrivProtocol         types.String `tfsdk:"priv_protocol"`
+	PrivKey              types.String `tfsdk:"priv_key"`
+	AuthenticationStatus types.String `tfsdk:"authentication_status"`
+}
+
+// Metadata returns the resource type name
+func (r *SNMPCredentialsResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_snmp_credentials"
+}
+
+// Schema defines the schema for the resource
+func (r *SNMPCredentialsResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Manages the SNMP credentials Domotz uses to query a device. " +
+			"Communities and keys are never returned by Domotz, so changes made outside Terraform are not detected.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Description: "Resource ID (format: agent_id:device_id)",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"agent_id": schema.Int64Attribute{
+				Description: "ID of the collector managing the device",
+				Required:    true,
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.RequiresReplace(),
+				},
+			},
+			"device_id": schema.Int64Attribute{
+				Description: "ID of the device",
+				Required:    true,
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.RequiresReplace(),
+				},
+			},
+			"version": schema.StringAttribute{
+				Description: "SNMP version (V1, V2C, V3)",
+				Required:    true,
+				Validators: []validator.String{
+					stringvalidator.OneOf("V1", "V2C", "V3"),
+				},
+			},
+			"community": schema.StringAttribute{
+				Description: "Read community, required for V1 and V2C",
+				Optional:    true,
+				Sensitive:   true,
+			},
+			"username": schema.StringAttribute{
+				Description: "Security name, required for V3",
+				Optional:    true,
+			},
+			"auth_protocol": schema.StringAttribute{
+				Description: "V3 authentication protocol (MD5, SHA)",
+				Optional:    true,
+				Validators: []validator.String{
+					stringvalidator.OneOf("MD5", "SHA"),
+				},
+			},
+			"auth_key": schema.StringAttribute{
+				Description: "V3 authentication key, required with auth_protocol",
+				Optional:    true,
+				Sensitive:   true,
+			},
+			"priv_protocol": schema.StringAttribute{
+				Description: "V3 privacy (encryption) protocol (DES, AES). Requires authentication.",
+				Optional:    true,
+				Validators: []validator.String{
+					stringvalidator.OneOf("DES", "AES"),
+				},
+			},
+			"priv_key": schema.StringAttribute{
+				Description: "V3 privacy key, required with priv_protocol",
+				Optional:    true,
+				Sensitive:   true,
+			},
+			"authentication_status": schema.StringAttribute{
+				Description: "Authentication status the collector reports for the device, e.g. AUTHENTICATED or WRONG_CREDENTIALS",
+				Computed:    true,
+			},
+		},
+	}
+}
+
+// ValidateConfig checks that the arguments match the SNMP version
+func (r *SNMPCredentialsResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config SNMPCredentialsResourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() || config.Version.IsUnknown() {
+		return
+	}
+
+	set := func(v types.String) bool { return !v.IsNull() }
+	requireSet := func(name string, v types.String, why string) {
+		if v.IsNull() {
+			resp.Diagnostics.AddAttributeError(path.Root(name), "Missing SNMP argument", fmt.Sprintf("%s is required %s.", name, why))
+		}
+	}
+	requireUnset := func(name string, v types.String, why string) {
+		if set(v) {
+			resp.Diagnostics.AddAttributeError(path.Root(name), "Invalid SNMP argument", fmt.Sprintf("%s cannot be set %s.", name, why))
+		}
+	}
+
+	switch version := config.Version.ValueString(); version {
+	case "V1", "V2C":
+		why := "with version " + version
+		requireSet("community", config.Community, why)
+		requireUnset("username", config.Username, why)
+		requireUnset("auth_protocol", config.AuthProtocol, why)
+		requireUnset("auth_key", config.AuthKey, why)
+		requireUnset("priv_protocol", config.PrivProtocol, why)
+		requireUnset("priv_key", config.PrivKey, why)
+	case "V3":
+		requireSet("username", config.Username, "with version V3")
+		requireUnset("community", config.Community, "with version V3")
+		if set(config.AuthProtocol) != set(config.AuthKey) {
+			requireSet("auth_protocol", config.AuthProtocol, "with auth_key")
+			requireSet("auth_key", config.AuthKey, "with auth_protocol")
+		}
+		if set(config.PrivProtocol) != set(config.PrivKey) {
+			requireSet("priv_protocol", config.PrivProtocol, "with priv_key")
+			requireSet("priv_key", config.PrivKey, "with priv_protocol")
+		}
+		if set(config.PrivProtocol) {
+			requireSet("auth_protocol", config.AuthProtocol, "with priv_protocol")
+		}
+	}
+}
+
+// Configure adds the provider configured client to the resource
+func (r *SNMPCredentialsResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	c, ok := req.ProviderData.(*client.Client)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *client.Client, got: %T", req.ProviderData),
+		)
+		return
+	}
+
+	r.client = c
+}
+
+// Create sets the SNMP credentials of the device
+func (r *SNMPCredentialsResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan SNMPCredentialsResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	agentID := int32(plan.AgentID.ValueInt64())
+	deviceID := int32(plan.DeviceID.ValueInt64())
+
+	r.apply(ctx, &plan, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	plan.ID = types.StringValue(fmt.Sprintf("%d:%d", agentID, deviceID))
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+// Read refreshes the version, username and protocols. Communities and keys
+// are write-only and kept from state.
+func (r *SNMPCredentialsResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state SNMPCredentialsResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	agentID := int32(state.AgentID.ValueInt64())
+	deviceID := int32(state.DeviceID.ValueInt64())
+
+	auth, err := r.client.GetSNMPAuthentication(ctx, agentID, deviceID)
+	if err != nil {
+		var notFound *client.NotFoundError
+		if errors.As(err, &notFound) {
+			// The device is gone, or its credentials were removed
+			resp.State.RemoveResource(ctx)
+			return
+		}
+		resp.Diagnostics.AddError(
+			"Error reading SNMP credentials",
+			"Could not read SNMP credentials: "+apiErrorDetail(err),
+		)
+		return
+	}
+
+	switch auth.Version {
+	case client.SNMPVersionV1:
+		state.Version = types.StringValue("V1")
+	case client.SNMPVersionV2:
+		state.Version = types.StringValue("V2C")
+	default:
+		state.Version = types.StringValue("V3")
+	}
+	state.Username = stringOrNull(auth.Username)
+	state.AuthProtocol = stringOrNull(auth.AuthenticationProtocol)
+	state.PrivProtocol = stringOrNull(auth.EncryptionProtocol)
+
+	device, err := r.client.GetDevice(ctx, agentID, deviceID)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error reading device",
+			"Could not read device authentication status: "+apiErrorDetail(err),
+		)
+		return
+	}
+	state.AuthenticationStatus = types.StringValue(device.AuthenticationStatus)
+	state.ID = types.StringValue(fmt.Sprintf("%d:%d", agentID, deviceID))
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+// Update replaces the SNMP credentials of the device
+func (r *SNMPCredentialsResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan SNMPCredentialsResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.apply(ctx, &plan, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+// Delete removes the SNMP credentials, so the collector falls back to its defaults
+func (r *SNMPCredentialsResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state SNMPCredentialsResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	err := r.client.DeleteSNMPAuthentication(ctx, int32(state.AgentID.ValueInt64()), int32(state.DeviceID.ValueInt64()))
+	if err != nil {
+		var notFound *client.NotFoundError
+		if errors.As(err, &notFound) {
+			return
+		}
+		resp.Diagnostics.AddError(
+			"Error deleting SNMP credentials",
+			"Could not delete SNMP credentials: "+apiErrorDetail(err),
+		)
+		return
+	}
+}
+
+// ImportState imports the resource into Terraform state
+func (r *SNMPCredentialsResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	// Import format: "agent_id:device_id"
+	parts := strings.Split(req.ID, ":")
+	if len(parts) != 2 {
+		resp.Diagnostics.AddError(
+			"Invalid import ID",
+			"Import ID must be in the format 'agent_id:device_id'",
+		)
+		return
+	}
+
+	agentID, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid agent ID", err.Error())
+		return
+	}
+
+	deviceID, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid device ID", err.Error())
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("agent_id"), agentID)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("device_id"), deviceID)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), req.ID)...)
+}
+
+// apply sends the planned credentials and records the resulting
+// authentication status in plan
+func (r *SNMPCredentialsResource) apply(ctx context.Context, plan *SNMPCredentialsResourceModel, diags *diag.Diagnostics) {
+	agentID := int32(plan.AgentID.ValueInt64())
+	deviceID := int32(plan.DeviceID.ValueInt64())
+
+	if err := r.client.SetSNMPAuthentication(ctx, agentID, deviceID, snmpAuthentication(*plan)); err != nil {
+		diags.AddError(
+			"Error setting SNMP credentials",
+			"Could not set SNMP credentials: "+apiErrorDetail(err),
+		)
+		return
+	}
+
+	device, err := r.client.GetDevice(ctx, agentID, deviceID)
+	if err != nil {
+		diags.AddError(
+			"Error reading device",
+			"Could not read device authentication status: "+apiErrorDetail(err),
+		)
+		return
+	}
+	plan.AuthenticationStatus = types.StringValue(device.AuthenticationStatus)
+}
+
+// snmpAuthentication maps the resource arguments onto the API's version names
+func snmpAuthentication(m SNMPCredentialsResourceModel) client.SNMPAuthentication {
+	switch m.Version.ValueString() {
+	case "V1":
+		return client.SNMPAuthentication{Version: client.SNMPVersionV1, ReadCommunity: m.Community.ValueString()}
+	case "V2C":
+		return client.SNMPAuthentication{Version: client.SNMPVersionV2, ReadCommunity: m.Community.ValueString()}
+	}
+
+	auth := client.SNMPAuthentication{
+		Version:                client.SNMPVersionV3NoAuth,
+		Username:               m.Username.ValueString(),
+		AuthenticationProtocol: m.AuthProtocol.ValueString(),
+		AuthenticationKey:      m.AuthKey.ValueString(),
+		EncryptionProtocol:     m.PrivProtocol.ValueString(),
+		EncryptionKey:          m.PrivKey.ValueString(),
+	}
+	switch {
+	case !m.PrivProtocol.IsNull():
+		auth.Version = client.SNMPVersionV3AuthPriv
+	case !m.AuthProtocol.IsNull():
+		auth.Version = client.SNMPVersionV3AuthNoPriv
+	}
+	return auth
+}
@@ -0,0 +1,146 @@
+package provider
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/domotz/terraform-provider-domotz/internal/client"
+	"github.com/domotz/terraform-provider-domotz/internal/mockapi"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+)
+
+func TestAccSNMPCredentialsResource(t *testing.T) {
+	server := testAccMockServer(t)
+	device := server.AddDevice(client.Device{AgentID: testAccAgentID, DisplayName: "switch-01"})
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy: func(s *terraform.State) error {
+			if auth, ok := server.SNMPAuthentication(testAccAgentID, device.ID); ok {
+				return fmt.Errorf("SNMP authentication still set: %+v", auth)
+			}
+			return nil
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: testAccSNMPCredentialsV2CConfig(device.ID, "s3cret"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("domotz_snmp_credentials.test", "id", fmt.Sprintf("%d:%d", testAccAgentID, device.ID)),
+					resource.TestCheckResourceAttr("domotz_snmp_credentials.test", "version", "V2C"),
+					resource.TestCheckResourceAttr("domotz_snmp_credentials.test", "authentication_status", mockapi.AuthenticationStatusAuthenticated),
+					func(*terraform.State) error {
+						auth, _ := server.SNMPAuthentication(testAccAgentID, device.ID)
+						if auth.Version != client.SNMPVersionV2 || auth.ReadCommunity != "s3cret" {
+							return fmt.Errorf("expected V2 with community s3cret, got %+v", auth)
+						}
+						return nil
+					},
+				),
+			},
+			{
+				// Communities and keys are write-only
+				ResourceName:            "domotz_snmp_credentials.test",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"community", "auth_key", "priv_key"},
+			},
+			{
+				Config: testAccSNMPCredentialsV3Config(device.ID),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("domotz_snmp_credentials.test", "version", "V3"),
+					resource.TestCheckResourceAttr("domotz_snmp_credentials.test", "username", "monitor"),
+					resource.TestCheckResourceAttr("domotz_snmp_credentials.test", "auth_protocol", "SHA"),
+					resource.TestCheckResourceAttr("domotz_snmp_credentials.test", "priv_protocol", "AES"),
+					resource.TestCheckNoResourceAttr("domotz_snmp_credentials.test", "community"),
+					func(*terraform.State) error {
+						auth, _ := server.SNMPAuthentication(testAccAgentID, device.ID)
+						if auth.Version != client.SNMPVersionV3AuthPriv || auth.AuthenticationKey != "authpass1" || auth.EncryptionKey != "privpass1" {
+							return fmt.Errorf("expected V3_AUTH_PRIV with both keys, got %+v", auth)
+						}
+						return nil
+					},
+				),
+			},
+			{
+				// The collector reports that the credentials stopped working
+				PreConfig:    func() { server.SetAuthenticationStatus(testAccAgentID, device.ID, "WRONG_CREDENTIALS") },
+				RefreshState: true,
+				Check:        resource.TestCheckResourceAttr("domotz_snmp_credentials.test", "authentication_status", "WRONG_CREDENTIALS"),
+			},
+			{
+				PreConfig: func() {
+					server.SetSNMPAuthentication(testAccAgentID, device.ID, client.SNMPAuthentication{
+						Version:       client.SNMPVersionV1,
+						ReadCommunity: "public",
+					})
+				},
+				Config:             testAccSNMPCredentialsV3Config(device.ID),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+		},
+	})
+}
+
+func TestAccSNMPCredentialsResource_invalid(t *testing.T) {
+	server := testAccMockServer(t)
+	device := server.AddDevice(client.Device{AgentID: testAccAgentID, DisplayName: "switch-01"})
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+resource "domotz_snmp_credentials" "test" {
+  agent_id  = %d
+  device_id = %d
+  version   = "V2C"
+  username  = "monitor"
+}
+`, testAccAgentID, device.ID),
+				ExpectError: regexp.MustCompile(`community is required with version V2C`),
+			},
+			{
+				Config: fmt.Sprintf(`
+resource "domotz_snmp_credentials" "test" {
+  agent_id      = %d
+  device_id     = %d
+  version       = "V3"
+  username      = "monitor"
+  priv_protocol = "AES"
+  priv_key      = "privpass1"
+}
+`, testAccAgentID, device.ID),
+				ExpectError: regexp.MustCompile(`auth_protocol is required with priv_protocol`),
+			},
+		},
+	})
+}
+
+func testAccSNMPCredentialsV2CConfig(deviceID int32, community string) string {
+	return fmt.Sprintf(`
+resource "domotz_snmp_credentials" "test" {
+  agent_id  = %d
+  device_id = %d
+  version   = "V2C"
+  community = %q
+}
+`, testAccAgentID, deviceID, community)
+}
+
+func testAccSNMPCredentialsV3Config(deviceID int32) string {
+	return fmt.Sprintf(`
+resource "domotz_snmp_credentials" "test" {
+  agent_id      = %d
+  device_id     = %d
+  version       = "V3"
+  username      = "monitor"
+  auth_protocol = "SHA"
+  auth_key      = "authpass1"
+  priv_protocol = "AES"
+  priv_key      = "privpass1"
+}
+`, testAccAgentID, deviceID)
+}
@@ -153,7 +153,7 @@ func (r *SNMPSensorResource) Create(ctx context.Context, req resource.CreateRequ
 		createReq,
 	)
 	if err != nil {
-		resp.Diagnostics.AddError("Error creating SNMP sensor", err.Error())
+		resp.Diagnostics.AddError("Error creating SNMP sensor", apiErrorDetail(err))
 		return
 	}
 
@@ -186,7 +186,7 @@ func (r *SNMPSensorResource) Read(ctx context.Context, req resource.ReadRequest,
 			resp.State.RemoveResource(ctx)
 			return
 		}
-		resp.Diagnostics.AddError("Error reading SNMP sensor", err.Error())
+		resp.Diagnostics.AddError("Error reading SNMP sensor", apiErrorDetail(err))
 		return
 	}
 
@@ -230,7 +230,7 @@ func (r *SNMPSensorResource) Delete(ctx context.Context, req resource.DeleteRequ
 		if errors.As(err, &notFound) {
 			return
 		}
-		resp.Diagnostics.AddError("Error deleting SNMP sensor", err.Error())
+		resp.Diagnostics.AddError("Error deleting SNMP sensor", apiErrorDetail(err))
 		return
 	}
 }
@@ -0,0 +1,78 @@
+package provider
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/domotz/terraform-provider-domotz/internal/client"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+)
+
+func TestAccSNMPSensorResource(t *testing.T) {
+	server := testAccMockServer(t)
+	device := server.AddDevice(client.Device{AgentID: testAccAgentID, DisplayName: "core-switch"})
+	var sensorID int32
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy: func(s *terraform.State) error {
+			if sensors := server.SNMPSensors(testAccAgentID, device.ID); len(sensors) != 0 {
+				return fmt.Errorf("SNMP sensors still exist: %+v", sensors)
+			}
+			return nil
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: testAccSNMPSensorConfig(device.ID, "1.3.6.1.2.1.1.1.0"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrSet("domotz_snmp_sensor.test", "id"),
+					resource.TestCheckResourceAttr("domotz_snmp_sensor.test", "oid", "1.3.6.1.2.1.1.1.0"),
+					resource.TestCheckResourceAttr("domotz_snmp_sensor.test", "category", "OTHER"),
+					resource.TestCheckResourceAttr("domotz_snmp_sensor.test", "value_type", "STRING"),
+					testAccCaptureID("domotz_snmp_sensor.test", "id", &sensorID),
+				),
+			},
+			{
+				ResourceName:      "domotz_snmp_sensor.test",
+				ImportState:       true,
+				ImportStateIdFunc: testAccImportStateIDFunc("domotz_snmp_sensor.test", "agent_id", "device_id", "id"),
+				ImportStateVerify: true,
+			},
+			{
+				// Changing the OID replaces the sensor
+				Config: testAccSNMPSensorConfig(device.ID, "1.3.6.1.2.1.1.5.0"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("domotz_snmp_sensor.test", "oid", "1.3.6.1.2.1.1.5.0"),
+					testAccCaptureID("domotz_snmp_sensor.test", "id", &sensorID),
+					func(*terraform.State) error {
+						sensors := server.SNMPSensors(testAccAgentID, device.ID)
+						if len(sensors) != 1 || sensors[0].ID != sensorID {
+							return fmt.Errorf("expected only sensor %d in API, got %+v", sensorID, sensors)
+						}
+						return nil
+					},
+				),
+			},
+			{
+				PreConfig:          func() { server.DeleteSNMPSensor(testAccAgentID, device.ID, sensorID) },
+				Config:             testAccSNMPSensorConfig(device.ID, "1.3.6.1.2.1.1.5.0"),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+		},
+	})
+}
+
+func testAccSNMPSensorConfig(deviceID int32, oid string) string {
+	return fmt.Sprintf(`
+resource "domotz_snmp_sensor" "test" {
+  agent_id   = %d
+  device_id  = %d
+  name       = "System Description"
+  oid        = %q
+  category   = "OTHER"
+  value_type = "STRING"
+}
+`, testAccAgentID, deviceID, oid)
+}
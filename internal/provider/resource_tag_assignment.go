@@ -0,0 +1,317 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/domotz/terraform-provider-domotz/internal/client"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var (
+	_ resource.Resource                = &TagAssignmentResource{}
+	_ resource.ResourceWithImportState = &TagAssignmentResource{}
+)
+
+// NewTagAssignmentResource is a helper function to simplify the provider implementation
+func NewTagAssignmentResource() resource.Resource {
+	return &TagAssignmentResource{}
+}
+
+// TagAssignmentResource binds one tag to a set of devices across collectors
+type TagAssignmentResource struct {
+	client *client.Client
+}
+
+// TagAssignmentResourceModel describes the resource data model
+type TagAssignmentResourceModel struct {
+	ID      types.String               `tfsdk:"id"`
+	TagID   types.Int64                `tfsdk:"tag_id"`
+	Members []TagAssignmentMemberModel `tfsdk:"members"`
+}
+
+// TagAssignmentMemberModel describes one device in members
+type TagAssignmentMemberModel struct {
+	AgentID  types.Int64 `tfsdk:"agent_id"`
+	DeviceID types.Int64 `tfsdk:"device_id"`
+}
+
+// Metadata returns the resource type name
+func (r *TagAssignmentResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_tag_assignment"
+}
+
+// Schema defines the schema for the resource
+func (r *TagAssignmentResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Binds one tag to a set of devices, possibly on different collectors. " +
+			"Bindings are applied concurrently and failures are reported per device. " +
+			"Devices not listed in members are left untouched.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Description: "Resource ID (the tag ID)",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"tag_id": schema.Int64Attribute{
+				Description: "ID of the tag",
+				Required:    true,
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.RequiresReplace(),
+				},
+			},
+			"members": schema.SetNestedAttribute{
+				Description: "Devices the tag is bound to",
+				Required:    true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"agent_id": schema.Int64Attribute{
+							Description: "ID of the collector managing the device",
+							Required:    true,
+						},
+						"device_id": schema.Int64Attribute{
+							Description: "ID of the device",
+							Required:    true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// Configure adds the provider configured client to the resource
+func (r *TagAssignmentResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	c, ok := req.ProviderData.(*client.Client)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *client.Client, got: %T", req.ProviderData),
+		)
+		return
+	}
+
+	r.client = c
+}
+
+// Create binds the tag to every member. Members that could not be bound are
+// left out of state and reported as warnings rather than errors, which would
+// taint the resource, so the next apply retries only those members.
+func (r *TagAssignmentResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan TagAssignmentResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tagID := int32(plan.TagID.ValueInt64())
+	want := memberRefs(plan.Members)
+
+	var bindDiags diag.Diagnostics
+	bound := r.bind(ctx, tagID, want, &bindDiags)
+	for _, d := range bindDiags {
+		resp.Diagnostics.AddWarning(d.Summary(), d.Detail())
+	}
+
+	plan.ID = types.StringValue(strconv.Itoa(int(tagID)))
+	plan.Members = memberModels(bound)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+// Read keeps the members that still carry the tag
+func (r *TagAssignmentResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state TagAssignmentResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tagID := int32(state.TagID.ValueInt64())
+
+	if _, err := r.client.GetTag(ctx, tagID); err != nil {
+		var notFound *client.NotFoundError
+		if errors.As(err, &notFound) {
+			// The tag is gone, and its bindings with it
+			resp.State.RemoveResource(ctx)
+			return
+		}
+		resp.Diagnostics.AddError("Error reading tag", apiErrorDetail(err))
+		return
+	}
+
+	tagged, err := r.client.DevicesWithTag(ctx, tagID, memberRefs(state.Members))
+	if err != nil {
+		resp.Diagnostics.AddError("Error reading tag assignment", apiErrorDetail(err))
+		return
+	}
+
+	state.ID = types.StringValue(strconv.Itoa(int(tagID)))
+	state.Members = memberModels(tagged)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+// Update unbinds removed members and binds added ones. State records what
+// actually happened, so failed members show up again in the next plan.
+func (r *TagAssignmentResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan, state TagAssignmentResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tagID := int32(plan.TagID.ValueInt64())
+	want := memberSet(memberRefs(plan.Members))
+	have := memberSet(memberRefs(state.Members))
+
+	var toUnbind, toBind []client.DeviceRef
+	for d := range have {
+		if !want[d] {
+			toUnbind = append(toUnbind, d)
+		}
+	}
+	for d := range want {
+		if !have[d] {
+			toBind = append(toBind, d)
+		}
+	}
+	sortDeviceRefs(toUnbind)
+	sortDeviceRefs(toBind)
+
+	for _, d := range r.unbind(ctx, tagID, toUnbind, &resp.Diagnostics) {
+		delete(have, d)
+	}
+	for _, d := range r.bind(ctx, tagID, toBind, &resp.Diagnostics) {
+		have[d] = true
+	}
+
+	members := make([]client.DeviceRef, 0, len(have))
+	for d := range have {
+		members = append(members, d)
+	}
+	sortDeviceRefs(members)
+
+	plan.ID = types.StringValue(strconv.Itoa(int(tagID)))
+	plan.Members = memberModels(members)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+// Delete unbinds the tag from every member
+func (r *TagAssignmentResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state TagAssignmentResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.unbind(ctx, int32(state.TagID.ValueInt64()), memberRefs(state.Members), &resp.Diagnostics)
+}
+
+// bind binds the tag to devices, adds one error per failed device and
+// returns the devices that were bound
+func (r *TagAssignmentResource) bind(ctx context.Context, tagID int32, devices []client.DeviceRef, diags *diag.Diagnostics) []client.DeviceRef {
+	var done []client.DeviceRef
+	for i, err := range r.client.BindTagToDevices(ctx, tagID, devices) {
+		if err != nil {
+			diags.AddError(
+				"Error binding tag to device",
+				fmt.Sprintf("Could not bind tag %d to device %d on collector %d: %s",
+					tagID, devices[i].DeviceID, devices[i].AgentID, apiErrorDetail(err)),
+			)
+			continue
+		}
+		done = append(done, devices[i])
+	}
+	return done
+}
+
+// unbind removes the tag from devices, adds one error per failed device and
+// returns the devices that no longer carry the tag
+func (r *TagAssignmentResource) unbind(ctx context.Context, tagID int32, devices []client.DeviceRef, diags *diag.Diagnostics) []client.DeviceRef {
+	var done []client.DeviceRef
+	for i, err := range r.client.UnbindTagFromDevices(ctx, tagID, devices) {
+		if err != nil {
+			diags.AddError(
+				"Error unbinding tag from device",
+				fmt.Sprintf("Could not unbind tag %d from device %d on collector %d: %s",
+					tagID, devices[i].DeviceID, devices[i].AgentID, apiErrorDetail(err)),
+			)
+			continue
+		}
+		done = append(done, devices[i])
+	}
+	return done
+}
+
+// ImportState imports the resource into Terraform state. The API cannot list
+// the devices carrying a tag, so members start empty and the next apply binds
+// the configured members; binding an already bound device is a no-op.
+func (r *TagAssignmentResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	tagID, err := strconv.ParseInt(req.ID, 10, 64)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Invalid import ID",
+			"Import ID must be the tag ID: "+err.Error(),
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("tag_id"), tagID)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), req.ID)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("members"), []TagAssignmentMemberModel{})...)
+}
+
+func memberRefs(members []TagAssignmentMemberModel) []client.DeviceRef {
+	refs := make([]client.DeviceRef, 0, len(members))
+	for _, m := range members {
+		refs = append(refs, client.DeviceRef{
+			AgentID:  int32(m.AgentID.ValueInt64()),
+			DeviceID: int32(m.DeviceID.ValueInt64()),
+		})
+	}
+	return refs
+}
+
+func memberModels(refs []client.DeviceRef) []TagAssignmentMemberModel {
+	members := make([]TagAssignmentMemberModel, 0, len(refs))
+	for _, d := range refs {
+		members = append(members, TagAssignmentMemberModel{
+			AgentID:  types.Int64Value(int64(d.AgentID)),
+			DeviceID: types.Int64Value(int64(d.DeviceID)),
+		})
+	}
+	return members
+}
+
+func memberSet(refs []client.DeviceRef) map[client.DeviceRef]bool {
+	set := make(map[client.DeviceRef]bool, len(refs))
+	for _, d := range refs {
+		set[d] = true
+	}
+	return set
+}
+
+func sortDeviceRefs(refs []client.DeviceRef) {
+	sort.Slice(refs, func(i, j int) bool {
+		if refs[i].AgentID != refs[j].AgentID {
+			return refs[i].AgentID < refs[j].AgentID
+		}
+		return refs[i].DeviceID < refs[j].DeviceID
+	})
+}
@@ -0,0 +1,154 @@
+package provider
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/domotz/terraform-provider-domotz/internal/client"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+)
+
+const testAccBranchAgentID = 200892
+
+func TestAccTagAssignmentResource(t *testing.T) {
+	server := testAccMockServer(t)
+	server.AddAgent(client.Agent{ID: testAccBranchAgentID, DisplayName: "Branch Collector"})
+	tag := server.AddTag(client.Tag{Name: "Fleet", Colour: "blue"})
+
+	// Enough devices to keep every worker busy
+	var devices []client.DeviceRef
+	for i := 0; i < 20; i++ {
+		agentID := int32(testAccAgentID)
+		if i%2 == 1 {
+			agentID = testAccBranchAgentID
+		}
+		d := server.AddDevice(client.Device{AgentID: agentID, DisplayName: fmt.Sprintf("ap-%02d", i)})
+		devices = append(devices, client.DeviceRef{AgentID: agentID, DeviceID: d.ID})
+	}
+	first, rest := devices[:15], devices[5:]
+
+	expectTagged := func(want []client.DeviceRef) resource.TestCheckFunc {
+		return func(*terraform.State) error {
+			wanted := memberSet(want)
+			for _, d := range devices {
+				ids := server.DeviceTagIDs(d.AgentID, d.DeviceID)
+				if tagged := len(ids) == 1 && ids[0] == tag.ID; tagged != wanted[d] {
+					return fmt.Errorf("device %d on collector %d: expected tagged=%t, got tags %v", d.DeviceID, d.AgentID, wanted[d], ids)
+				}
+			}
+			return nil
+		}
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy: func(s *terraform.State) error {
+			for _, d := range devices {
+				if ids := server.DeviceTagIDs(d.AgentID, d.DeviceID); len(ids) != 0 {
+					return fmt.Errorf("device %d still has tags bound: %v", d.DeviceID, ids)
+				}
+			}
+			return nil
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: testAccTagAssignmentConfig(tag.ID, first),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("domotz_tag_assignment.test", "id", fmt.Sprint(tag.ID)),
+					resource.TestCheckResourceAttr("domotz_tag_assignment.test", "members.#", "15"),
+					resource.TestCheckTypeSetElemNestedAttrs("domotz_tag_assignment.test", "members.*", map[string]string{
+						"agent_id":  fmt.Sprint(testAccBranchAgentID),
+						"device_id": fmt.Sprint(first[1].DeviceID),
+					}),
+					expectTagged(first),
+				),
+			},
+			{
+				// Members cannot be discovered from the tag alone
+				ResourceName:            "domotz_tag_assignment.test",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"members"},
+			},
+			{
+				// Membership moves: five devices leave, five join
+				Config: testAccTagAssignmentConfig(tag.ID, rest),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("domotz_tag_assignment.test", "members.#", "15"),
+					expectTagged(rest),
+				),
+			},
+			{
+				PreConfig:          func() { server.UnbindTag(rest[0].AgentID, rest[0].DeviceID, tag.ID) },
+				Config:             testAccTagAssignmentConfig(tag.ID, rest),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				Config: testAccTagAssignmentConfig(tag.ID, rest),
+				Check:  expectTagged(rest),
+			},
+		},
+	})
+}
+
+func TestAccTagAssignmentResource_partialFailure(t *testing.T) {
+	server := testAccMockServer(t)
+	tag := server.AddTag(client.Tag{Name: "Fleet", Colour: "blue"})
+	device := server.AddDevice(client.Device{AgentID: testAccAgentID, DisplayName: "ap-01"})
+	members := []client.DeviceRef{
+		{AgentID: testAccAgentID, DeviceID: device.ID},
+		{AgentID: testAccAgentID, DeviceID: 999999},
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				// The failed member is a warning, left out of state
+				Config:             testAccTagAssignmentConfig(tag.ID, members),
+				Check:              resource.TestCheckResourceAttr("domotz_tag_assignment.test", "members.#", "1"),
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				// The resource is not tainted: the next apply updates it in
+				// place, retrying only the missing member
+				Config: testAccTagAssignmentConfig(tag.ID, members),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("domotz_tag_assignment.test", plancheck.ResourceActionUpdate),
+					},
+				},
+				ExpectError: regexp.MustCompile(fmt.Sprintf(`Could not bind tag %d to device 999999 on collector %d`, tag.ID, testAccAgentID)),
+			},
+			{
+				// The reachable member was bound despite the failure
+				PreConfig: func() {
+					if ids := server.DeviceTagIDs(testAccAgentID, device.ID); len(ids) != 1 || ids[0] != tag.ID {
+						t.Errorf("expected tag %d bound to device %d, got %v", tag.ID, device.ID, ids)
+					}
+				},
+				Config: testAccTagAssignmentConfig(tag.ID, members[:1]),
+				Check:  resource.TestCheckResourceAttr("domotz_tag_assignment.test", "members.#", "1"),
+			},
+		},
+	})
+}
+
+func testAccTagAssignmentConfig(tagID int32, members []client.DeviceRef) string {
+	var b strings.Builder
+	for _, m := range members {
+		fmt.Fprintf(&b, "    { agent_id = %d, device_id = %d },\n", m.AgentID, m.DeviceID)
+	}
+	return fmt.Sprintf(`
+resource "domotz_tag_assignment" "test" {
+  tag_id = %d
+  members = [
+%s  ]
+}
+`, tagID, b.String())
+}
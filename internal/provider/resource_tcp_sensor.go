@@ -116,8 +116,23 @@ func (r *TCPSensorResource) Create(ctx context.Context, req resource.CreateReque
 		int32(plan.DeviceID.ValueInt64()),
 		createReq,
 	)
+	if errors.Is(err, client.ErrConflict) {
+		// The port is already monitored on this device, adopt the existing sensor
+		sensor, err = r.client.GetTCPSensorByPort(
+			ctx,
+			int32(plan.AgentID.ValueInt64()),
+			int32(plan.DeviceID.ValueInt64()),
+			createReq.Port,
+		)
+		if err == nil {
+			resp.Diagnostics.AddWarning(
+				"Adopted existing TCP sensor",
+				fmt.Sprintf("Port %d was already monitored on this device; sensor %d is now managed by Terraform.", sensor.Port, sensor.ID),
+			)
+		}
+	}
 	if err != nil {
-		resp.Diagnostics.AddError("Error creating TCP sensor", err.Error())
+		resp.Diagnostics.AddError("Error creating TCP sensor", apiErrorDetail(err))
 		return
 	}
 
@@ -150,7 +165,7 @@ func (r *TCPSensorResource) Read(ctx context.Context, req resource.ReadRequest,
 			resp.State.RemoveResource(ctx)
 			return
 		}
-		resp.Diagnostics.AddError("Error reading TCP sensor", err.Error())
+		resp.Diagnostics.AddError("Error reading TCP sensor", apiErrorDetail(err))
 		return
 	}
 
@@ -191,7 +206,7 @@ func (r *TCPSensorResource) Delete(ctx context.Context, req resource.DeleteReque
 		if errors.As(err, &notFound) {
 			return
 		}
-		resp.Diagnostics.AddError("Error deleting TCP sensor", err.Error())
+		resp.Diagnostics.AddError("Error deleting TCP sensor", apiErrorDetail(err))
 		return
 	}
 }
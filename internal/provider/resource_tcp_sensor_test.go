@@ -0,0 +1,106 @@
+package provider
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/domotz/terraform-provider-domotz/internal/client"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+)
+
+func TestAccTCPSensorResource(t *testing.T) {
+	server := testAccMockServer(t)
+	device := server.AddDevice(client.Device{AgentID: testAccAgentID, DisplayName: "web"})
+	var sensorID int32
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy: func(s *terraform.State) error {
+			if sensors := server.TCPSensors(testAccAgentID, device.ID); len(sensors) != 0 {
+				return fmt.Errorf("TCP sensors still exist: %+v", sensors)
+			}
+			return nil
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: testAccTCPSensorConfig(device.ID, 443),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrSet("domotz_tcp_sensor.test", "id"),
+					resource.TestCheckResourceAttr("domotz_tcp_sensor.test", "port", "443"),
+					testAccCaptureID("domotz_tcp_sensor.test", "id", &sensorID),
+				),
+			},
+			{
+				ResourceName:      "domotz_tcp_sensor.test",
+				ImportState:       true,
+				ImportStateIdFunc: testAccImportStateIDFunc("domotz_tcp_sensor.test", "agent_id", "device_id", "id"),
+				ImportStateVerify: true,
+			},
+			{
+				// Changing the port replaces the sensor
+				Config: testAccTCPSensorConfig(device.ID, 8443),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("domotz_tcp_sensor.test", "port", "8443"),
+					testAccCaptureID("domotz_tcp_sensor.test", "id", &sensorID),
+					func(*terraform.State) error {
+						sensors := server.TCPSensors(testAccAgentID, device.ID)
+						if len(sensors) != 1 || sensors[0].Port != 8443 {
+							return fmt.Errorf("expected only port 8443 in API, got %+v", sensors)
+						}
+						return nil
+					},
+				),
+			},
+			{
+				PreConfig:          func() { server.DeleteTCPSensor(testAccAgentID, device.ID, sensorID) },
+				Config:             testAccTCPSensorConfig(device.ID, 8443),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+		},
+	})
+}
+
+func TestAccTCPSensorResource_adoptsConflict(t *testing.T) {
+	server := testAccMockServer(t)
+	device := server.AddDevice(client.Device{AgentID: testAccAgentID, DisplayName: "web"})
+	existing := server.AddTCPSensor(testAccAgentID, client.TCPSensor{DeviceID: device.ID, Port: 22})
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccTCPSensorConfig(device.ID, 22),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("domotz_tcp_sensor.test", "id", fmt.Sprint(existing.ID)),
+				),
+			},
+		},
+	})
+}
+
+func TestAccTCPSensorResource_invalidPort(t *testing.T) {
+	testAccMockServer(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccTCPSensorConfig(1, 70000),
+				ExpectError: regexp.MustCompile(`must be between 1 and 65535`),
+			},
+		},
+	})
+}
+
+func testAccTCPSensorConfig(deviceID int32, port int) string {
+	return fmt.Sprintf(`
+resource "domotz_tcp_sensor" "test" {
+  agent_id  = %d
+  device_id = %d
+  port      = %d
+}
+`, testAccAgentID, deviceID, port)
+}
@@ -0,0 +1,250 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+
+	"github.com/domotz/terraform-provider-domotz/internal/client"
+	"github.com/hashicorp/terraform-plugin-framework-validators/mapvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var (
+	_ resource.Resource                = &WebhookContactResource{}
+	_ resource.ResourceWithImportState = &WebhookContactResource{}
+)
+
+// NewWebhookContactResource is a helper function to simplify the provider implementation
+func NewWebhookContactResource() resource.Resource {
+	return &WebhookContactResource{}
+}
+
+// WebhookContactResource defines the resource implementation
+type WebhookContactResource struct {
+	client *client.Client
+}
+
+// WebhookContactResourceModel describes the resource data model
+type WebhookContactResourceModel struct {
+	ID      types.String `tfsdk:"id"`
+	Name    types.String `tfsdk:"name"`
+	URL     types.String `tfsdk:"url"`
+	Headers types.Map    `tfsdk:"headers"`
+}
+
+// Metadata returns the resource type name
+func (r *WebhookContactResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_webhook_contact"
+}
+
+// Schema defines the schema for the resource
+func (r *WebhookContactResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Manages a webhook contact that receives Domotz alerts, e.g. a Slack or Teams " +
+			"incoming webhook. The URL and headers usually carry credentials and are marked sensitive.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Description: "Contact ID",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"name": schema.StringAttribute{
+				Description: "Contact name",
+				Required:    true,
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+			"url": schema.StringAttribute{
+				Description: "URL alerts are POSTed to",
+				Required:    true,
+				Sensitive:   true,
+				Validators: []validator.String{
+					stringvalidator.RegexMatches(regexp.MustCompile(`^https?://[^/]+`), "must be an http or https URL"),
+				},
+			},
+			"headers": schema.MapAttribute{
+				Description: "HTTP headers sent with every alert, e.g. an Authorization token",
+				Optional:    true,
+				Sensitive:   true,
+				ElementType: types.StringType,
+				Validators: []validator.Map{
+					mapvalidator.SizeAtLeast(1),
+				},
+			},
+		},
+	}
+}
+
+// Configure adds the provider configured client to the resource
+func (r *WebhookContactResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	c, ok := req.ProviderData.(*client.Client)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *client.Client, got: %T", req.ProviderData),
+		)
+		return
+	}
+
+	r.client = c
+}
+
+// Create creates the resource and sets the initial Terraform state
+func (r *WebhookContactResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan WebhookContactResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	contactReq := webhookContactRequest(ctx, plan, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	contact, err := r.client.CreateWebhookContact(ctx, contactReq)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error creating webhook contact",
+			"Could not create webhook contact: "+apiErrorDetail(err),
+		)
+		return
+	}
+
+	plan.ID = types.StringValue(strconv.Itoa(int(contact.ID)))
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+// Read refreshes the Terraform state with the latest data
+func (r *WebhookContactResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state WebhookContactResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	contactID, err := strconv.ParseInt(state.ID.ValueString(), 10, 32)
+	if err != nil {
+		resp.Diagnostics.AddError("Error parsing contact ID", err.Error())
+		return
+	}
+
+	contact, err := r.client.GetWebhookContact(ctx, int32(contactID))
+	if err != nil {
+		var notFound *client.NotFoundError
+		if errors.As(err, &notFound) {
+			resp.State.RemoveResource(ctx)
+			return
+		}
+		resp.Diagnostics.AddError(
+			"Error reading webhook contact",
+			"Could not read webhook contact: "+apiErrorDetail(err),
+		)
+		return
+	}
+
+	state.Name = types.StringValue(contact.Name)
+	state.URL = types.StringValue(contact.URL)
+	state.Headers = types.MapNull(types.StringType)
+	if len(contact.Headers) > 0 {
+		headers, diags := types.MapValueFrom(ctx, types.StringType, contact.Headers)
+		resp.Diagnostics.Append(diags...)
+		state.Headers = headers
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+// Update updates the resource and sets the updated Terraform state on success
+func (r *WebhookContactResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan WebhookContactResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	contactID, err := strconv.ParseInt(plan.ID.ValueString(), 10, 32)
+	if err != nil {
+		resp.Diagnostics.AddError("Error parsing contact ID", err.Error())
+		return
+	}
+
+	contactReq := webhookContactRequest(ctx, plan, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// The API replaces the whole contact, so removed headers are dropped
+	_, err = r.client.UpdateWebhookContact(ctx, int32(contactID), contactReq)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error updating webhook contact",
+			"Could not update webhook contact: "+apiErrorDetail(err),
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+// Delete deletes the resource and removes the Terraform state on success
+func (r *WebhookContactResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state WebhookContactResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	contactID, err := strconv.ParseInt(state.ID.ValueString(), 10, 32)
+	if err != nil {
+		resp.Diagnostics.AddError("Error parsing contact ID", err.Error())
+		return
+	}
+
+	err = r.client.DeleteWebhookContact(ctx, int32(contactID))
+	if err != nil {
+		var notFound *client.NotFoundError
+		if errors.As(err, &notFound) {
+			return
+		}
+		resp.Diagnostics.AddError(
+			"Error deleting webhook contact",
+			"Could not delete webhook contact: "+apiErrorDetail(err),
+		)
+		return
+	}
+}
+
+// ImportState imports the resource into Terraform state
+func (r *WebhookContactResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+}
+
+func webhookContactRequest(ctx context.Context, plan WebhookContactResourceModel, diags *diag.Diagnostics) client.WebhookContactRequest {
+	req := client.WebhookContactRequest{
+		Name: plan.Name.ValueString(),
+		URL:  plan.URL.ValueString(),
+	}
+	if !plan.Headers.IsNull() {
+		diags.Append(plan.Headers.ElementsAs(ctx, &req.Headers, false)...)
+	}
+	return req
+}
@@ -0,0 +1,94 @@
+package provider
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+)
+
+func TestAccWebhookContactResource(t *testing.T) {
+	server := testAccMockServer(t)
+	var contactID int32
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy: func(s *terraform.State) error {
+			if _, ok := server.WebhookContact(contactID); ok {
+				return fmt.Errorf("webhook contact %d still exists", contactID)
+			}
+			return nil
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: testAccWebhookContactConfig("PagerDuty bridge", "https://bridge.example.com/alerts", `headers = { Authorization = "Bearer s3cret" }`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrSet("domotz_webhook_contact.test", "id"),
+					resource.TestCheckResourceAttr("domotz_webhook_contact.test", "url", "https://bridge.example.com/alerts"),
+					resource.TestCheckResourceAttr("domotz_webhook_contact.test", "headers.Authorization", "Bearer s3cret"),
+					testAccCaptureID("domotz_webhook_contact.test", "id", &contactID),
+				),
+			},
+			{
+				ResourceName:      "domotz_webhook_contact.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			{
+				// Rotating the URL and dropping the headers updates in place
+				Config: testAccWebhookContactConfig("PagerDuty bridge", "https://bridge.example.com/v2/alerts", ""),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckNoResourceAttr("domotz_webhook_contact.test", "headers.%"),
+					func(*terraform.State) error {
+						c, ok := server.WebhookContact(contactID)
+						if !ok || c.URL != "https://bridge.example.com/v2/alerts" || len(c.Headers) != 0 {
+							return fmt.Errorf("unexpected webhook contact in API: %+v", c)
+						}
+						return nil
+					},
+				),
+			},
+			{
+				PreConfig: func() {
+					c, _ := server.WebhookContact(contactID)
+					c.URL = "https://attacker.example.com/"
+					server.UpdateWebhookContact(c)
+				},
+				Config:             testAccWebhookContactConfig("PagerDuty bridge", "https://bridge.example.com/v2/alerts", ""),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+		},
+	})
+}
+
+func TestAccWebhookContactResource_invalidURL(t *testing.T) {
+	testAccMockServer(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccWebhookContactConfig("Slack", "hooks.slack.com/services/T000", ""),
+				ExpectError: regexp.MustCompile(`must be an http or https URL`),
+			},
+			{
+				// Omit headers instead: an empty map would read back as null
+				Config:      testAccWebhookContactConfig("Slack", "https://hooks.slack.com/services/T000", "headers = {}"),
+				ExpectError: regexp.MustCompile(`map must contain at least 1 elements`),
+			},
+		},
+	})
+}
+
+func testAccWebhookContactConfig(name, url, headers string) string {
+	return fmt.Sprintf(`
+resource "domotz_webhook_contact" "test" {
+  name = %q
+  url  = %q
+  %s
+}
+`, name, url, headers)
+}
@@ -0,0 +1,164 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/domotz/terraform-provider-domotz/internal/client"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// userDataFromAPI maps API user_data to the nested attribute. Unset fields
+// are null, and the block itself is null when nothing is set unless keepEmpty.
+// typeName is the catalogue label of ud.Type.
+func userDataFromAPI(ud client.DeviceUserData, typeName string, keepEmpty bool) *UserDataModel {
+	if ud == (client.DeviceUserData{}) && !keepEmpty {
+		return nil
+	}
+	return &UserDataModel{
+		Name:   stringOrNull(ud.Name),
+		Model:  stringOrNull(ud.Model),
+		Vendor: stringOrNull(ud.Vendor),
+		Type:   deviceTypeOrNull(typeName),
+	}
+}
+
+// readUserData is userDataFromAPI for resources. The type is always read back
+// as its catalogue label; DeviceTypeValue semantic equality keeps a prior
+// spelling (ID or other casing) of the same type.
+func readUserData(ctx context.Context, c *client.Client, ud client.DeviceUserData, keepEmpty bool) *UserDataModel {
+	return userDataFromAPI(ud, deviceTypeLabel(ctx, c, ud.Type), keepEmpty)
+}
+
+// userDataUpdate resolves the planned and prior types and returns the
+// user_data fields to send, or nil if none changed
+func userDataUpdate(ctx context.Context, c *client.Client, plan, state *UserDataModel) (*client.UpdateDeviceUserData, error) {
+	planTypeID, err := deviceTypeID(ctx, c, userDataType(plan))
+	if err != nil {
+		return nil, err
+	}
+	// A stale state type that no longer resolves is treated as changed
+	stateTypeID, err := deviceTypeID(ctx, c, userDataType(state))
+	if err != nil {
+		stateTypeID = -1
+	}
+	return userDataChanges(plan, state, planTypeID, stateTypeID), nil
+}
+
+// userDataChanges returns the user_data fields that differ between plan and
+// state, with removed fields set to their empty value, or nil if none changed.
+// The type is compared by resolved ID, since "Router" and "7" are the same type.
+func userDataChanges(plan, state *UserDataModel, planTypeID, stateTypeID int32) *client.UpdateDeviceUserData {
+	if plan == nil {
+		plan = &UserDataModel{}
+	}
+	if state == nil {
+		state = &UserDataModel{}
+	}
+
+	var changes client.UpdateDeviceUserData
+	changed := false
+	if v := plan.Name.ValueString(); v != state.Name.ValueString() {
+		changes.Name, changed = &v, true
+	}
+	if v := plan.Model.ValueString(); v != state.Model.ValueString() {
+		changes.Model, changed = &v, true
+	}
+	if v := plan.Vendor.ValueString(); v != state.Vendor.ValueString() {
+		changes.Vendor, changed = &v, true
+	}
+	if planTypeID != stateTypeID {
+		changes.Type, changed = &planTypeID, true
+	}
+	if !changed {
+		return nil
+	}
+	return &changes
+}
+
+// validateUserDataType checks a planned user_data.type against the device
+// type catalogue, so an unknown type fails at plan time rather than during apply
+func validateUserDataType(ctx context.Context, c *client.Client, plan tfsdk.Plan, diags *diag.Diagnostics) {
+	var userData types.Object
+	diags.Append(plan.GetAttribute(ctx, path.Root("user_data"), &userData)...)
+	if diags.HasError() || userData.IsNull() || userData.IsUnknown() {
+		return
+	}
+
+	typePath := path.Root("user_data").AtName("type")
+	var planType DeviceTypeValue
+	diags.Append(plan.GetAttribute(ctx, typePath, &planType)...)
+	if diags.HasError() || planType.IsNull() || planType.IsUnknown() {
+		return
+	}
+
+	deviceType, err := c.ResolveDeviceType(ctx, planType.ValueString())
+	if err != nil {
+		var notFound *client.NotFoundError
+		if errors.As(err, &notFound) {
+			diags.AddAttributeError(typePath, "Invalid device type",
+				fmt.Sprintf("%q is not a known device type. Use a label or ID listed by the domotz_device_types data source.", planType.ValueString()))
+			return
+		}
+		diags.AddAttributeError(typePath, "Error resolving device type", apiErrorDetail(err))
+		return
+	}
+	rememberDeviceType(deviceType)
+}
+
+// userDataType returns the type attribute of a possibly absent user_data block
+func userDataType(ud *UserDataModel) types.String {
+	if ud == nil {
+		return types.StringNull()
+	}
+	return ud.Type.StringValue
+}
+
+// deviceTypeID resolves a user_data.type value to its numeric ID, or 0 when unset
+func deviceTypeID(ctx context.Context, c *client.Client, value types.String) (int32, error) {
+	if value.IsNull() || value.IsUnknown() || value.ValueString() == "" {
+		return 0, nil
+	}
+	deviceType, err := c.ResolveDeviceType(ctx, value.ValueString())
+	if err != nil {
+		return 0, err
+	}
+	rememberDeviceType(deviceType)
+	return deviceType.ID, nil
+}
+
+// deviceTypeLabel returns the catalogue label for a device type ID. It falls
+// back to the numeric ID if the catalogue is unavailable or lacks the type,
+// and returns "" for 0 (unset).
+func deviceTypeLabel(ctx context.Context, c *client.Client, id int32) string {
+	if id == 0 {
+		return ""
+	}
+	deviceType, err := c.ResolveDeviceType(ctx, strconv.Itoa(int(id)))
+	if err != nil {
+		return strconv.Itoa(int(id))
+	}
+	rememberDeviceType(deviceType)
+	return deviceType.Label
+}
+
+func stringOrNull(s string) types.String {
+	if s == "" {
+		return types.StringNull()
+	}
+	return types.StringValue(s)
+}
+
+// timeOrNull formats t as RFC 3339, or null when the API left it unset
+func timeOrNull(t time.Time) types.String {
+	if t.IsZero() {
+		return types.StringNull()
+	}
+	return types.StringValue(t.UTC().Format(time.RFC3339))
+}